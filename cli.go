@@ -0,0 +1,141 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"otto/db"
+)
+
+const usage = `Usage:
+  otto                                   start the terminal UI
+  otto export [flags] [name...]          export saved connections as JSON
+  otto import [flags] <file>             import connections into history
+
+Export flags:
+  -o <file>        write to file instead of stdout
+  -group <name>    only export connections in this group
+  -no-passwords    leave passwords out of the export
+
+Import flags:
+  -format <fmt>    auto, otto, dbeaver, pgadmin or pgservice (default auto)
+
+Connections are matched by name or address (case-insensitive substring).
+Imported connections that already exist in history are skipped.
+`
+
+func runCommand(args []string) int {
+	switch args[0] {
+	case "export":
+		return runExport(args[1:])
+	case "import":
+		return runImport(args[1:])
+	case "help", "-h", "-help", "--help":
+		fmt.Print(usage)
+		return 0
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", args[0], usage)
+	return 2
+}
+
+func matchesAny(cfg db.Config, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	name := strings.ToLower(cfg.Name + " " + db.DisplayName(cfg))
+	for _, p := range patterns {
+		if strings.Contains(name, strings.ToLower(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	out := fs.String("o", "", "")
+	group := fs.String("group", "", "")
+	noPasswords := fs.Bool("no-passwords", false, "")
+	fs.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	history, err := db.LoadHistory()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	var selected []db.Config
+	for _, cfg := range history {
+		if *group != "" && !strings.EqualFold(cfg.Group, *group) {
+			continue
+		}
+		if matchesAny(cfg, fs.Args()) {
+			selected = append(selected, cfg)
+		}
+	}
+	if len(selected) == 0 {
+		fmt.Fprintln(os.Stderr, "no matching connections")
+		return 1
+	}
+
+	var w io.Writer = os.Stdout
+	var f *os.File
+	if *out != "" {
+		f, err = os.OpenFile(*out, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		w = f
+	}
+	err = db.ExportConnections(w, selected, !*noPasswords)
+	if f != nil {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if *out != "" {
+		fmt.Fprintf(os.Stderr, "✓ exported %d connection(s) to %s\n", len(selected), *out)
+	}
+	return 0
+}
+
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := fs.String("format", string(db.FormatAuto), "")
+	fs.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	cfgs, detected, err := db.ParseConnections(data, db.ImportFormat(*format))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	added, skipped, err := db.ImportConnections(cfgs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Printf("✓ %s: imported %d connection(s), skipped %d duplicate(s)\n", detected, added, skipped)
+	return 0
+}
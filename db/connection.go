@@ -3,16 +3,30 @@ package db
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	Name     string `json:"name,omitempty"`
-	Driver   Driver `json:"driver,omitempty"`
-	Host     string `json:"host,omitempty"`
-	Port     string `json:"port,omitempty"`
-	User     string `json:"user,omitempty"`
-	Password string `json:"password,omitempty"`
-	DBName   string `json:"dbname,omitempty"`
+	ID         string    `json:"id,omitempty"`
+	Name       string    `json:"name,omitempty"`
+	Group      string    `json:"group,omitempty"`
+	Driver     Driver    `json:"driver,omitempty"`
+	Host       string    `json:"host,omitempty"`
+	Port       string    `json:"port,omitempty"`
+	Socket     string    `json:"socket,omitempty"`
+	User       string    `json:"user,omitempty"`
+	Password   string    `json:"password,omitempty"`
+	DBName     string    `json:"dbname,omitempty"`
+	Env        string    `json:"env,omitempty"`
+	EnvColor   string    `json:"env_color,omitempty"`
+	ReadOnly   bool      `json:"read_only,omitempty"`
+	Guard      []Hazard  `json:"guard,omitempty"`
+	TimeFormat string    `json:"time_format,omitempty"`
+	TimeZone   string    `json:"time_zone,omitempty"`
+	LastUsed   time.Time `json:"last_used,omitzero"`
 }
 
 func (c Config) DSN() string {
@@ -27,6 +41,9 @@ func (c Config) DSN() string {
 		if user == "" {
 			user = "root"
 		}
+		if c.Socket != "" {
+			return fmt.Sprintf("%s:%s@unix(%s)/%s?parseTime=true", user, c.Password, c.Socket, dbname)
+		}
 		port := c.Port
 		if port == "" {
 			port = "3306"
@@ -44,14 +61,34 @@ func (c Config) DSN() string {
 	if dbname == "" {
 		dbname = "postgres"
 	}
+	if c.Socket != "" {
+		dir, sockPort := postgresSocketDir(c.Socket)
+		if sockPort != "" {
+			port = sockPort
+		}
+		return fmt.Sprintf("postgres://%s:%s@/%s?host=%s&port=%s", user, c.Password, dbname, url.QueryEscape(dir), port)
+	}
 	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s", user, c.Password, host, port, dbname)
 }
 
-func Connect(ctx context.Context, cfg Config) (DB, error) {
-	switch cfg.Driver {
-	case DriverMySQL:
-		return newMysqlDB(cfg.DSN())
-	default:
-		return newPgxDB(ctx, cfg.DSN())
+func postgresSocketDir(socket string) (string, string) {
+	base := filepath.Base(socket)
+	if port, ok := strings.CutPrefix(base, ".s.PGSQL."); ok {
+		return filepath.Dir(socket), port
 	}
+	return socket, ""
+}
+
+func (c Config) Endpoint() string {
+	if c.Socket != "" {
+		return c.Socket
+	}
+	if c.Host == "" {
+		return "localhost"
+	}
+	return c.Host
+}
+
+func Connect(ctx context.Context, cfg Config) (DB, error) {
+	return newReconnectingDB(ctx, cfg)
 }
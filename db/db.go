@@ -14,11 +14,38 @@ type SortOption struct {
 	Desc   bool
 }
 
+type ColumnFilter struct {
+	Column string
+	Value  string
+}
+
 type DB interface {
+	ListDatabases(ctx context.Context) ([]string, error)
+	SwitchDatabase(ctx context.Context, name string) error
+	ListSchemas(ctx context.Context) ([]string, error)
+	ListBaseTables(ctx context.Context, schema string) ([]Object, error)
+	ListViews(ctx context.Context, schema string) ([]Object, error)
+	ListMaterializedViews(ctx context.Context, schema string) ([]Object, error)
+	ListSchemaIndexes(ctx context.Context, schema string) ([]Object, error)
+	ListSequences(ctx context.Context, schema string) ([]Object, error)
+	ListFunctions(ctx context.Context, schema string) ([]Object, error)
+	ListTriggers(ctx context.Context, schema string) ([]Object, error)
+	ObjectDDL(ctx context.Context, o Object) (string, error)
 	ListTables(ctx context.Context) ([]Table, error)
 	ListColumns(ctx context.Context) ([]Column, error)
-	FetchTableData(ctx context.Context, schema, table string, limit, offset int, sort *SortOption) (*QueryResult, error)
+	ListSchemaColumns(ctx context.Context, schema string) ([]Column, error)
+	ListTableColumns(ctx context.Context, schema, table string) ([]Column, error)
+	ListIndexes(ctx context.Context, schema, table string) ([]Index, error)
+	ListConstraints(ctx context.Context, schema, table string) ([]Constraint, error)
+	ListForeignKeys(ctx context.Context, schema, table string) ([]ForeignKey, error)
+	ListReferencingKeys(ctx context.Context, schema, table string) ([]ForeignKey, error)
+	ListSchemaForeignKeys(ctx context.Context, schema string) ([]ForeignKey, error)
+	ListTableTriggers(ctx context.Context, schema, table string) ([]Trigger, error)
+	FetchTableData(ctx context.Context, schema, table string, limit, offset int, sort *SortOption, filters []ColumnFilter) (*QueryResult, error)
+	CountRows(ctx context.Context, schema, table string, filters []ColumnFilter) (int64, error)
+	ProfileColumn(ctx context.Context, schema, table, column string, filters []ColumnFilter) (*ColumnProfile, error)
 	ExecQuery(ctx context.Context, query string) (*QueryResult, error)
+	Ping(ctx context.Context) error
 	Close(ctx context.Context) error
 }
 
@@ -28,12 +55,33 @@ type Table struct {
 }
 
 type Column struct {
-	Schema string
-	Table  string
-	Name   string
+	Schema     string
+	Table      string
+	Name       string
+	DataType   string
+	Nullable   bool
+	Default    string
+	Position   int
+	PrimaryKey bool
+	Comment    string
 }
 
 type QueryResult struct {
 	Columns []string
 	Rows    [][]string
+	// Raw holds the values unaffected by time_format and time_zone when
+	// those are set, in a form the database reads back.
+	Raw   [][]string
+	Nulls [][]bool
+}
+
+func (r *QueryResult) IsNull(row, col int) bool {
+	return row < len(r.Nulls) && col < len(r.Nulls[row]) && r.Nulls[row][col]
+}
+
+func (r *QueryResult) RawValue(row, col int) string {
+	if row < len(r.Raw) {
+		return r.Raw[row][col]
+	}
+	return r.Rows[row][col]
 }
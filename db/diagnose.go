@@ -0,0 +1,424 @@
+package db
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type StepStatus int
+
+const (
+	StepOK StepStatus = iota
+	StepWarn
+	StepFail
+	StepSkipped
+)
+
+type DiagStep struct {
+	Name    string
+	Status  StepStatus
+	Detail  string
+	Hint    string
+	Elapsed time.Duration
+}
+
+type Diagnosis struct {
+	Steps         []DiagStep
+	ServerVersion string
+	Latency       time.Duration
+}
+
+func (d Diagnosis) OK() bool {
+	for _, s := range d.Steps {
+		if s.Status == StepFail {
+			return false
+		}
+	}
+	return true
+}
+
+const diagTimeout = 5 * time.Second
+
+func defaultPort(driver Driver) string {
+	if driver == DriverMySQL {
+		return "3306"
+	}
+	return "5432"
+}
+
+func Diagnose(ctx context.Context, cfg Config) Diagnosis {
+	var d Diagnosis
+	add := func(s DiagStep) { d.Steps = append(d.Steps, s) }
+	skip := func(names ...string) {
+		for _, n := range names {
+			add(DiagStep{Name: n, Status: StepSkipped})
+		}
+	}
+
+	if cfg.Socket != "" {
+		step := diagnoseSocket(ctx, cfg)
+		add(step)
+		if step.Status == StepFail {
+			skip("Authentication", "Database")
+			return d
+		}
+	} else {
+		host := cfg.Host
+		if host == "" {
+			host = "localhost"
+		}
+		port := cfg.Port
+		if port == "" {
+			port = defaultPort(cfg.Driver)
+		}
+
+		step := diagnoseDNS(ctx, host)
+		add(step)
+		if step.Status == StepFail {
+			skip("TCP", "TLS", "Authentication", "Database")
+			return d
+		}
+
+		conn, step := diagnoseTCP(ctx, cfg.Driver, host, port)
+		add(step)
+		if conn == nil {
+			skip("TLS", "Authentication", "Database")
+			return d
+		}
+		step, version := diagnoseTLS(conn, cfg.Driver, host, port)
+		conn.Close()
+		add(step)
+		d.ServerVersion = version
+		if step.Status == StepFail {
+			skip("Authentication", "Database")
+			return d
+		}
+	}
+
+	auth, database, conn := diagnoseLogin(ctx, cfg)
+	add(auth)
+	add(database)
+	if conn == nil {
+		return d
+	}
+	defer conn.Close(ctx)
+
+	if res, err := conn.ExecQuery(ctx, "SELECT version()"); err == nil && len(res.Rows) > 0 && len(res.Rows[0]) > 0 {
+		d.ServerVersion = res.Rows[0][0]
+	}
+	var total time.Duration
+	succeeded := 0
+	for range 3 {
+		start := time.Now()
+		if err := conn.Ping(ctx); err != nil {
+			break
+		}
+		total += time.Since(start)
+		succeeded++
+	}
+	if succeeded > 0 {
+		d.Latency = total / time.Duration(succeeded)
+	}
+	return d
+}
+
+func diagnoseSocket(ctx context.Context, cfg Config) DiagStep {
+	path := cfg.Socket
+	if cfg.Driver != DriverMySQL {
+		dir, port := postgresSocketDir(cfg.Socket)
+		if port == "" {
+			port = cfg.Port
+			if port == "" {
+				port = "5432"
+			}
+		}
+		path = filepath.Join(dir, ".s.PGSQL."+port)
+	}
+	step := DiagStep{Name: "Socket", Detail: path}
+	if _, err := os.Stat(path); err != nil {
+		step.Status = StepFail
+		step.Detail = err.Error()
+		step.Hint = "is the server running locally? check unix_socket_directories / socket in the server config"
+		return step
+	}
+	start := time.Now()
+	dialer := net.Dialer{Timeout: diagTimeout}
+	conn, err := dialer.DialContext(ctx, "unix", path)
+	step.Elapsed = time.Since(start)
+	if err != nil {
+		step.Status = StepFail
+		step.Detail = err.Error()
+		if errors.Is(err, syscall.EACCES) {
+			step.Hint = "permission denied — your user needs access to the socket file"
+		}
+		return step
+	}
+	conn.Close()
+	return step
+}
+
+func diagnoseDNS(ctx context.Context, host string) DiagStep {
+	step := DiagStep{Name: "DNS"}
+	if ip := net.ParseIP(host); ip != nil {
+		step.Detail = host + " (IP address)"
+		return step
+	}
+	lctx, cancel := context.WithTimeout(ctx, diagTimeout)
+	defer cancel()
+	start := time.Now()
+	addrs, err := net.DefaultResolver.LookupHost(lctx, host)
+	step.Elapsed = time.Since(start)
+	if err != nil {
+		step.Status = StepFail
+		step.Detail = err.Error()
+		step.Hint = "check the host name, or your VPN / DNS settings"
+		return step
+	}
+	step.Detail = host + " → " + strings.Join(addrs, ", ")
+	return step
+}
+
+func diagnoseTCP(ctx context.Context, driver Driver, host, port string) (net.Conn, DiagStep) {
+	step := DiagStep{Name: "TCP"}
+	addr := net.JoinHostPort(host, port)
+	dialer := net.Dialer{Timeout: diagTimeout}
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	step.Elapsed = time.Since(start)
+	if err == nil {
+		step.Detail = addr + " reachable"
+		return conn, step
+	}
+
+	step.Status = StepFail
+	step.Detail = err.Error()
+	var netErr net.Error
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED):
+		step.Hint = fmt.Sprintf("nothing is listening on %s — is the server running and the port correct?", addr)
+	case errors.As(err, &netErr) && netErr.Timeout():
+		step.Hint = "connection timed out — a firewall or security group is probably dropping traffic"
+	}
+	if hint := portHint(driver, port); hint != "" {
+		step.Hint = hint
+	}
+	return nil, step
+}
+
+func portHint(driver Driver, port string) string {
+	switch {
+	case driver == DriverMySQL && port == "5432":
+		return "5432 is the PostgreSQL default port — MySQL usually listens on 3306"
+	case driver != DriverMySQL && port == "3306":
+		return "3306 is the MySQL default port — PostgreSQL usually listens on 5432"
+	}
+	return ""
+}
+
+func diagnoseTLS(conn net.Conn, driver Driver, host, port string) (DiagStep, string) {
+	step := DiagStep{Name: "TLS"}
+	_ = conn.SetDeadline(time.Now().Add(diagTimeout))
+	start := time.Now()
+	var (
+		offered bool
+		version string
+		err     error
+	)
+	if driver == DriverMySQL {
+		offered, version, err = mysqlStartTLS(conn)
+	} else {
+		offered, err = postgresStartTLS(conn)
+	}
+	if err != nil {
+		step.Status = StepFail
+		step.Detail = err.Error()
+		step.Hint = fmt.Sprintf("the server on port %s does not speak the %s protocol", port, driverLabel(driver))
+		if hint := portHint(driver, port); hint != "" {
+			step.Hint = hint
+		}
+		return step, ""
+	}
+	if !offered {
+		step.Status = StepWarn
+		step.Detail = "server does not offer TLS — traffic is unencrypted"
+		return step, version
+	}
+
+	tlsConn := tls.Client(conn, &tls.Config{ServerName: host, InsecureSkipVerify: true})
+	if err := tlsConn.Handshake(); err != nil {
+		step.Status = StepWarn
+		step.Detail = "handshake failed: " + err.Error()
+		return step, version
+	}
+	step.Elapsed = time.Since(start)
+	state := tlsConn.ConnectionState()
+	step.Detail = tls.VersionName(state.Version) + " · " + tls.CipherSuiteName(state.CipherSuite)
+	if len(state.PeerCertificates) > 0 {
+		if err := state.PeerCertificates[0].VerifyHostname(host); err != nil {
+			step.Status = StepWarn
+			step.Hint = "certificate does not match the host name: " + err.Error()
+		}
+	}
+	return step, version
+}
+
+func driverLabel(driver Driver) string {
+	if driver == DriverMySQL {
+		return "MySQL"
+	}
+	return "PostgreSQL"
+}
+
+func postgresStartTLS(conn net.Conn) (bool, error) {
+	req := make([]byte, 8)
+	binary.BigEndian.PutUint32(req[0:4], 8)
+	binary.BigEndian.PutUint32(req[4:8], 80877103)
+	if _, err := conn.Write(req); err != nil {
+		return false, err
+	}
+	resp := make([]byte, 1)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return false, fmt.Errorf("no reply to SSLRequest: %w", err)
+	}
+	switch resp[0] {
+	case 'S':
+		return true, nil
+	case 'N':
+		return false, nil
+	}
+	return false, fmt.Errorf("unexpected reply %q to SSLRequest", resp[0])
+}
+
+const (
+	mysqlClientProtocol41     = 0x00000200
+	mysqlClientSSL            = 0x00000800
+	mysqlClientSecureConn     = 0x00008000
+	mysqlHandshakeProtocolV10 = 10
+)
+
+func mysqlStartTLS(conn net.Conn) (bool, string, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return false, "", fmt.Errorf("no handshake from server: %w", err)
+	}
+	size := int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+	if size == 0 || size > 1<<16 {
+		return false, "", fmt.Errorf("unexpected handshake packet size %d", size)
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(conn, payload); err != nil {
+		return false, "", err
+	}
+	if payload[0] == 0xFF {
+		return false, "", fmt.Errorf("server refused connection: %s", strings.TrimSpace(string(payload[3:])))
+	}
+	if payload[0] != mysqlHandshakeProtocolV10 {
+		return false, "", fmt.Errorf("unexpected handshake protocol %d", payload[0])
+	}
+	end := 1
+	for end < len(payload) && payload[end] != 0 {
+		end++
+	}
+	version := string(payload[1:end])
+	capsAt := end + 1 + 4 + 8 + 1
+	if capsAt+2 > len(payload) {
+		return false, version, nil
+	}
+	caps := uint32(binary.LittleEndian.Uint16(payload[capsAt : capsAt+2]))
+	if caps&mysqlClientSSL == 0 {
+		return false, version, nil
+	}
+
+	req := make([]byte, 4+32)
+	req[0] = 32
+	req[3] = header[3] + 1
+	binary.LittleEndian.PutUint32(req[4:8], mysqlClientSSL|mysqlClientProtocol41|mysqlClientSecureConn)
+	binary.LittleEndian.PutUint32(req[8:12], 1<<24)
+	req[12] = 45
+	if _, err := conn.Write(req); err != nil {
+		return false, version, err
+	}
+	return true, version, nil
+}
+
+func diagnoseLogin(ctx context.Context, cfg Config) (DiagStep, DiagStep, DB) {
+	auth := DiagStep{Name: "Authentication"}
+	database := DiagStep{Name: "Database"}
+
+	lctx, cancel := context.WithTimeout(ctx, 2*diagTimeout)
+	defer cancel()
+	start := time.Now()
+	conn, err := dial(lctx, cfg)
+	auth.Elapsed = time.Since(start)
+	if err == nil {
+		user := cfg.User
+		if user == "" {
+			user = defaultUser(cfg.Driver)
+		}
+		auth.Detail = "logged in as " + user
+		database.Detail = cfg.DBName
+		if database.Detail == "" {
+			database.Detail = "(server default)"
+		}
+		return auth, database, conn
+	}
+
+	var pgErr *pgconn.PgError
+	var myErr *mysql.MySQLError
+	switch {
+	case errors.As(err, &pgErr) && pgErr.Code == "3D000":
+		auth.Detail = "credentials accepted"
+		database.Status = StepFail
+		database.Detail = pgErr.Message
+		database.Hint = "create the database or pick an existing one"
+	case errors.As(err, &pgErr) && pgErr.Code == "28000":
+		auth.Status = StepFail
+		auth.Detail = pgErr.Message
+		auth.Hint = "the server's pg_hba.conf rejects this user/database/address — add a matching entry and reload"
+		database.Status = StepSkipped
+	case errors.As(err, &pgErr) && pgErr.Code == "28P01":
+		auth.Status = StepFail
+		auth.Detail = pgErr.Message
+		auth.Hint = "check the user name and password"
+		database.Status = StepSkipped
+	case errors.As(err, &myErr) && (myErr.Number == 1049 || myErr.Number == 1044):
+		auth.Detail = "credentials accepted"
+		database.Status = StepFail
+		database.Detail = myErr.Message
+		if myErr.Number == 1044 {
+			database.Hint = "the user has no privileges on this database"
+		} else {
+			database.Hint = "create the database or pick an existing one"
+		}
+	case errors.As(err, &myErr) && myErr.Number == 1045:
+		auth.Status = StepFail
+		auth.Detail = myErr.Message
+		auth.Hint = "check the user name, password and the host the user is allowed to connect from"
+		database.Status = StepSkipped
+	default:
+		auth.Status = StepFail
+		auth.Detail = err.Error()
+		database.Status = StepSkipped
+	}
+	return auth, database, nil
+}
+
+func defaultUser(driver Driver) string {
+	if driver == DriverMySQL {
+		return "root"
+	}
+	return "postgres"
+}
@@ -0,0 +1,74 @@
+package db
+
+import "strings"
+
+type Policy struct {
+	ConfirmWrites bool
+}
+
+type Environment struct {
+	Name   string
+	Color  string
+	Policy Policy
+}
+
+var knownEnvironments = map[string]Environment{
+	"dev":     {Name: "dev", Color: "#3FB950"},
+	"staging": {Name: "staging", Color: "#E3B341"},
+	"prod":    {Name: "prod", Color: "#FF4444", Policy: Policy{ConfirmWrites: true}},
+}
+
+var environmentAliases = map[string]string{
+	"development": "dev",
+	"local":       "dev",
+	"stage":       "staging",
+	"production":  "prod",
+	"live":        "prod",
+}
+
+const customEnvColor = "#8B949E"
+
+func (c Config) Environment() Environment {
+	name := strings.ToLower(strings.TrimSpace(c.Env))
+	if name == "" {
+		return Environment{}
+	}
+	if alias, ok := environmentAliases[name]; ok {
+		name = alias
+	}
+	env, ok := knownEnvironments[name]
+	if !ok {
+		env = Environment{Name: name, Color: customEnvColor}
+	}
+	if c.EnvColor != "" {
+		env.Color = c.EnvColor
+	}
+	return env
+}
+
+func (e Environment) IsProduction() bool {
+	return e.Name == "prod"
+}
+
+var defaultGuards = map[string][]Hazard{
+	"":        {HazardDeleteWithoutWhere, HazardUpdateWithoutWhere},
+	"dev":     {HazardDeleteWithoutWhere, HazardUpdateWithoutWhere},
+	"staging": {HazardDeleteWithoutWhere, HazardUpdateWithoutWhere, HazardTruncate, HazardDrop},
+	"prod":    AllHazards,
+}
+
+func (c Config) GuardedHazards() map[Hazard]bool {
+	rules := c.Guard
+	if rules == nil {
+		env := c.Environment()
+		var ok bool
+		if rules, ok = defaultGuards[env.Name]; !ok {
+			rules = defaultGuards["staging"]
+		}
+	}
+	guarded := make(map[Hazard]bool, len(rules))
+	for _, h := range rules {
+		guarded[h] = true
+	}
+	return guarded
+}
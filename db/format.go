@@ -0,0 +1,290 @@
+package db
+
+import (
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const defaultTimeLayout = "2006-01-02 15:04:05.999999"
+
+type valueFormat struct {
+	layout string
+	loc    *time.Location
+	server *time.Location
+}
+
+func newValueFormat(cfg Config) (valueFormat, error) {
+	f := valueFormat{layout: cfg.TimeFormat}
+	if cfg.TimeZone != "" {
+		loc, err := time.LoadLocation(cfg.TimeZone)
+		if err != nil {
+			return f, fmt.Errorf("time_zone: %w", err)
+		}
+		f.loc = loc
+	}
+	return f, nil
+}
+
+func (f valueFormat) timestamp(t time.Time, zoned bool) string {
+	if zoned && f.loc != nil {
+		t = t.In(f.loc)
+	}
+	if f.layout != "" {
+		return t.Format(f.layout)
+	}
+	s := t.Format(defaultTimeLayout)
+	if !zoned {
+		return s
+	}
+	if _, offset := t.Zone(); offset%3600 != 0 {
+		return s + t.Format("-07:00")
+	}
+	return s + t.Format("-07")
+}
+
+func (f valueFormat) pgValue(m *pgtype.Map, fd pgconn.FieldDescription, raw []byte, val any) string {
+	switch v := val.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	switch fd.DataTypeOID {
+	case pgtype.JSONOID:
+		return string(raw)
+	case pgtype.JSONBOID:
+		if fd.Format == pgtype.TextFormatCode {
+			return string(raw)
+		}
+		if len(raw) > 0 && raw[0] == 1 {
+			return string(raw[1:])
+		}
+	}
+	if t, ok := m.TypeForOID(fd.DataTypeOID); ok {
+		if c, ok := t.Codec.(*pgtype.ArrayCodec); ok {
+			var arr pgtype.Array[any]
+			if err := m.PlanScan(fd.DataTypeOID, fd.Format, &arr).Scan(raw, &arr); err == nil {
+				return f.pgArray(m, c.ElementType.OID, arr)
+			}
+		}
+	}
+	return f.pgText(m, fd.DataTypeOID, val)
+}
+
+func pgElementOID(m *pgtype.Map, oid uint32) uint32 {
+	t, ok := m.TypeForOID(oid)
+	if !ok {
+		return 0
+	}
+	switch c := t.Codec.(type) {
+	case *pgtype.RangeCodec:
+		return c.ElementType.OID
+	case *pgtype.MultirangeCodec:
+		return c.ElementType.OID
+	}
+	return 0
+}
+
+func (f valueFormat) pgText(m *pgtype.Map, oid uint32, val any) string {
+	switch v := val.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return v
+	case time.Time:
+		switch oid {
+		case pgtype.DateOID:
+			return v.Format(time.DateOnly)
+		case pgtype.TimestamptzOID:
+			return f.timestamp(v, true)
+		}
+		return f.timestamp(v, false)
+	case pgtype.InfinityModifier:
+		return v.String()
+	case pgtype.Interval:
+		return pgInterval(v)
+	case pgtype.Time:
+		return pgTimeOfDay(v.Microseconds)
+	case pgtype.Range[any]:
+		return f.pgRange(m, pgElementOID(m, oid), v)
+	case pgtype.Multirange[pgtype.Range[any]]:
+		elem := pgElementOID(m, pgElementOID(m, oid))
+		ranges := make([]string, len(v))
+		for i, r := range v {
+			ranges[i] = f.pgRange(m, elem, r)
+		}
+		return "{" + strings.Join(ranges, ",") + "}"
+	}
+	if b, err := m.Encode(oid, pgtype.TextFormatCode, val, nil); err == nil && b != nil {
+		return string(b)
+	}
+	if b, ok := val.([]byte); ok {
+		return `\x` + hex.EncodeToString(b)
+	}
+	return fmt.Sprintf("%v", val)
+}
+
+func (f valueFormat) pgArray(m *pgtype.Map, elem uint32, arr pgtype.Array[any]) string {
+	if len(arr.Dims) == 0 {
+		return "{}"
+	}
+	var b strings.Builder
+	if slices.ContainsFunc(arr.Dims, func(d pgtype.ArrayDimension) bool { return d.LowerBound != 1 }) {
+		for _, d := range arr.Dims {
+			fmt.Fprintf(&b, "[%d:%d]", d.LowerBound, d.LowerBound+d.Length-1)
+		}
+		b.WriteByte('=')
+	}
+	next := 0
+	var write func(dim int)
+	write = func(dim int) {
+		b.WriteByte('{')
+		for i := range int(arr.Dims[dim].Length) {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if dim+1 < len(arr.Dims) {
+				write(dim + 1)
+				continue
+			}
+			if v := arr.Elements[next]; v == nil {
+				b.WriteString("NULL")
+			} else {
+				b.WriteString(pgQuote(f.pgText(m, elem, v), true))
+			}
+			next++
+		}
+		b.WriteByte('}')
+	}
+	write(0)
+	return b.String()
+}
+
+func (f valueFormat) pgRange(m *pgtype.Map, elem uint32, r pgtype.Range[any]) string {
+	if r.LowerType == pgtype.Empty {
+		return "empty"
+	}
+	var b strings.Builder
+	b.WriteByte("(["[boolIndex(r.LowerType == pgtype.Inclusive)])
+	if r.LowerType != pgtype.Unbounded {
+		b.WriteString(pgQuote(f.pgText(m, elem, r.Lower), false))
+	}
+	b.WriteByte(',')
+	if r.UpperType != pgtype.Unbounded {
+		b.WriteString(pgQuote(f.pgText(m, elem, r.Upper), false))
+	}
+	b.WriteByte(")]"[boolIndex(r.UpperType == pgtype.Inclusive)])
+	return b.String()
+}
+
+func boolIndex(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// pgQuote double-quotes an array element or range bound when it is empty,
+// contains whitespace or a delimiter, or (in arrays) would read as NULL.
+// Arrays escape with a backslash, ranges by doubling the character.
+func pgQuote(s string, array bool) string {
+	special := `()[],"\`
+	if array {
+		special = `{},"\`
+	}
+	if s != "" && !strings.ContainsAny(s, special+" \t\n\r\v\f") && !(array && strings.EqualFold(s, "NULL")) {
+		return s
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		if r == '"' || r == '\\' {
+			if array {
+				b.WriteByte('\\')
+			} else {
+				b.WriteRune(r)
+			}
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func plural(n int64, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return strconv.FormatInt(n, 10) + " " + unit + "s"
+}
+
+func pgTimeOfDay(us int64) string {
+	sign := ""
+	if us < 0 {
+		sign, us = "-", -us
+	}
+	s := fmt.Sprintf("%s%02d:%02d:%02d", sign, us/3600e6, us/60e6%60, us/1e6%60)
+	if frac := us % 1e6; frac != 0 {
+		s += strings.TrimRight(fmt.Sprintf(".%06d", frac), "0")
+	}
+	return s
+}
+
+func pgInterval(iv pgtype.Interval) string {
+	var parts []string
+	if years := int64(iv.Months / 12); years != 0 {
+		parts = append(parts, plural(years, "year"))
+	}
+	if months := int64(iv.Months % 12); months != 0 {
+		parts = append(parts, plural(months, "mon"))
+	}
+	if iv.Days != 0 {
+		parts = append(parts, plural(int64(iv.Days), "day"))
+	}
+	if iv.Microseconds != 0 || len(parts) == 0 {
+		parts = append(parts, pgTimeOfDay(iv.Microseconds))
+	}
+	return strings.Join(parts, " ")
+}
+
+var mysqlBinaryTypes = map[string]bool{
+	"BINARY": true, "VARBINARY": true, "TINYBLOB": true, "BLOB": true, "MEDIUMBLOB": true, "LONGBLOB": true,
+}
+
+func (f valueFormat) mysqlValue(typeName string, val any) string {
+	switch v := val.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		if mysqlBinaryTypes[typeName] {
+			return "0x" + strings.ToUpper(hex.EncodeToString(v))
+		}
+		return string(v)
+	case time.Time:
+		if typeName == "DATE" {
+			if v.IsZero() {
+				return "0000-00-00"
+			}
+			return v.Format(time.DateOnly)
+		}
+		if v.IsZero() {
+			return "0000-00-00 00:00:00"
+		}
+		if typeName == "TIMESTAMP" && f.loc != nil && f.server != nil {
+			v = time.Date(v.Year(), v.Month(), v.Day(), v.Hour(), v.Minute(), v.Second(), v.Nanosecond(), f.server).In(f.loc)
+		}
+		return f.timestamp(v, false)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", val)
+}
@@ -0,0 +1,34 @@
+package db
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const guardLogFile = "guard.log"
+
+func guardLogPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, historyDir, guardLogFile)
+}
+
+func LogGuardDecision(cfg Config, d Destructive, decision string) {
+	p := guardLogPath()
+	_ = os.MkdirAll(filepath.Dir(p), 0700)
+	f, err := os.OpenFile(p, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	env := cfg.Environment().Name
+	if env == "" {
+		env = "-"
+	}
+	stmt := strings.Join(strings.Fields(d.Statement), " ")
+	fmt.Fprintf(f, "%s\t%s\t%s\t%s\t%s\t%s\n",
+		time.Now().Format(time.RFC3339), env, DisplayName(cfg), decision, d.Hazard, stmt)
+}
@@ -1,7 +1,13 @@
 package db
 
 import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 )
@@ -18,6 +24,9 @@ func DisplayName(cfg Config) string {
 	if cfg.Name != "" {
 		return cfg.Name
 	}
+	if cfg.Socket != "" {
+		return socketDisplayName(cfg)
+	}
 	host := cfg.Host
 	if host == "" {
 		host = "localhost"
@@ -47,73 +56,247 @@ func DisplayName(cfg Config) string {
 	return user + "@" + host + ":" + port + "/" + dbname
 }
 
+func socketDisplayName(cfg Config) string {
+	user := cfg.User
+	dbname := cfg.DBName
+	if cfg.Driver == DriverMySQL {
+		if user == "" {
+			user = "root"
+		}
+	} else {
+		if user == "" {
+			user = "postgres"
+		}
+		if dbname == "" {
+			dbname = "postgres"
+		}
+	}
+	return user + "@unix(" + cfg.Socket + ")/" + dbname
+}
+
 func matchKey(a, b Config) bool {
 	return DisplayName(a) == DisplayName(b)
 }
 
-func LoadHistory() []Config {
-	data, err := os.ReadFile(historyPath())
-	if err != nil {
-		return nil
+// WithSaved fills in the settings of saved that the connect form has no
+// fields for, so that saving from the form does not drop them.
+func (c Config) WithSaved(saved Config) Config {
+	if c.Guard == nil {
+		c.Guard = saved.Guard
 	}
-	var history []Config
-	if err := json.Unmarshal(data, &history); err != nil {
-		return nil
+	if c.TimeFormat == "" {
+		c.TimeFormat = saved.TimeFormat
 	}
-	return history
+	if c.TimeZone == "" {
+		c.TimeZone = saved.TimeZone
+	}
+	return c
 }
 
-func DeleteConnection(cfg Config) {
-	history := LoadHistory()
-	filtered := history[:0]
-	for _, h := range history {
-		if !matchKey(h, cfg) {
-			filtered = append(filtered, h)
+const historyVersion = 1
+
+var ErrHistoryCorrupt = errors.New("history file is corrupt")
+
+type historyDoc struct {
+	Version     int      `json:"version"`
+	Connections []Config `json:"connections"`
+}
+
+func newConnectionID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func readHistory() ([]Config, error) {
+	p := historyPath()
+	data, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var history []Config
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case len(trimmed) == 0:
+	case trimmed[0] == '[':
+		err = json.Unmarshal(trimmed, &history)
+	default:
+		var doc historyDoc
+		err = json.Unmarshal(trimmed, &doc)
+		if err == nil && doc.Version > historyVersion {
+			return nil, fmt.Errorf("%s was written by a newer otto (format v%d)", p, doc.Version)
 		}
+		history = doc.Connections
 	}
-	p := historyPath()
-	data, err := json.MarshalIndent(filtered, "", "  ")
 	if err != nil {
-		return
+		backup, berr := backupCorruptFile(p, data)
+		if berr != nil {
+			return nil, fmt.Errorf("%w: %v (backup failed: %v)", ErrHistoryCorrupt, err, berr)
+		}
+		return nil, fmt.Errorf("%w: %v (backed up to %s)", ErrHistoryCorrupt, err, backup)
 	}
-	_ = os.WriteFile(p, data, 0600)
+
+	seen := map[string]bool{}
+	for _, h := range history {
+		seen[h.ID] = true
+	}
+	for i := range history {
+		if history[i].ID == "" {
+			history[i].ID = legacyConnectionID(history[i], seen)
+		}
+	}
+	return history, nil
 }
 
-func UpdateConnection(index int, cfg Config) {
-	history := LoadHistory()
-	if index < 0 || index >= len(history) {
-		return
+// legacyConnectionID is stable across reads, so legacy entries keep their IDs until the file is rewritten.
+func legacyConnectionID(cfg Config, seen map[string]bool) string {
+	sum := sha256.Sum256([]byte(cfg.Name + "\x00" + DisplayName(cfg)))
+	id := hex.EncodeToString(sum[:8])
+	for n := 2; seen[id]; n++ {
+		id = fmt.Sprintf("%s-%d", hex.EncodeToString(sum[:8]), n)
 	}
-	history[index] = cfg
-	p := historyPath()
-	data, err := json.MarshalIndent(history, "", "  ")
+	seen[id] = true
+	return id
+}
+
+func backupCorruptFile(p string, data []byte) (string, error) {
+	info, err := os.Stat(p)
 	if err != nil {
-		return
+		return "", err
+	}
+	backup := fmt.Sprintf("%s.corrupt-%d", p, info.ModTime().Unix())
+	if _, err := os.Stat(backup); err == nil {
+		return backup, nil
 	}
-	_ = os.WriteFile(p, data, 0600)
+	return backup, os.WriteFile(backup, data, 0600)
 }
 
-func SaveConnection(cfg Config) {
-	history := LoadHistory()
+func writeHistory(history []Config) error {
+	data, err := json.MarshalIndent(historyDoc{Version: historyVersion, Connections: history}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(historyPath(), data)
+}
 
-	found := false
-	for i, h := range history {
-		if matchKey(h, cfg) {
-			history[i] = cfg
-			found = true
-			break
-		}
+func writeFileAtomic(p string, data []byte) error {
+	dir := filepath.Dir(p)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(p)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
 	}
-	if !found {
-		history = append([]Config{cfg}, history...)
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
 	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
 
+func updateHistory(fn func([]Config) ([]Config, error)) error {
 	p := historyPath()
-	_ = os.MkdirAll(filepath.Dir(p), 0700)
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return err
+	}
+	unlock, err := lockPath(p + ".lock")
+	if err != nil {
+		return fmt.Errorf("lock history: %w", err)
+	}
+	defer unlock()
 
-	data, err := json.MarshalIndent(history, "", "  ")
+	history, err := readHistory()
+	if err != nil && !errors.Is(err, ErrHistoryCorrupt) {
+		return err
+	}
+	history, err = fn(history)
 	if err != nil {
-		return
+		return err
 	}
-	_ = os.WriteFile(p, data, 0600)
+	return writeHistory(history)
+}
+
+func LoadHistory() ([]Config, error) {
+	return readHistory()
+}
+
+func DeleteConnection(id string) error {
+	return updateHistory(func(history []Config) ([]Config, error) {
+		filtered := history[:0]
+		for _, h := range history {
+			if h.ID != id {
+				filtered = append(filtered, h)
+			}
+		}
+		return filtered, nil
+	})
+}
+
+func UpdateConnection(id string, cfg Config) error {
+	return updateHistory(func(history []Config) ([]Config, error) {
+		for i, h := range history {
+			if h.ID == id {
+				cfg.ID = id
+				history[i] = cfg.WithSaved(h)
+				return history, nil
+			}
+		}
+		return nil, fmt.Errorf("connection %q no longer exists in history", DisplayName(cfg))
+	})
+}
+
+func SaveConnection(cfg Config) (string, error) {
+	err := updateHistory(func(history []Config) ([]Config, error) {
+		for i, h := range history {
+			if matchKey(h, cfg) {
+				cfg.ID = h.ID
+				history[i] = cfg.WithSaved(h)
+				return history, nil
+			}
+		}
+		if cfg.ID == "" {
+			cfg.ID = newConnectionID()
+		}
+		return append([]Config{cfg}, history...), nil
+	})
+	return cfg.ID, err
+}
+
+func ImportConnections(cfgs []Config) (added, skipped int, err error) {
+	err = updateHistory(func(history []Config) ([]Config, error) {
+		for _, cfg := range cfgs {
+			duplicate := false
+			for _, h := range history {
+				if matchKey(h, cfg) {
+					duplicate = true
+					break
+				}
+			}
+			if duplicate {
+				skipped++
+				continue
+			}
+			cfg.ID = newConnectionID()
+			history = append(history, cfg)
+			added++
+		}
+		return history, nil
+	})
+	return added, skipped, err
 }
@@ -9,3 +9,7 @@ func quotePostgresIdent(ident string) string {
 func quoteMySQLIdent(ident string) string {
 	return "`" + strings.ReplaceAll(ident, "`", "``") + "`"
 }
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const layoutsFile = "layouts.json"
+
+type ColumnLayout struct {
+	Order  []string       `json:"order,omitempty"`
+	Hidden []string       `json:"hidden,omitempty"`
+	Pinned int            `json:"pinned,omitempty"`
+	Widths map[string]int `json:"widths,omitempty"`
+}
+
+func (l ColumnLayout) IsZero() bool {
+	return len(l.Order) == 0 && len(l.Hidden) == 0 && l.Pinned == 0 && len(l.Widths) == 0
+}
+
+type layoutsDoc struct {
+	Version int                     `json:"version"`
+	Tables  map[string]ColumnLayout `json:"tables"`
+}
+
+var ErrLayoutsCorrupt = errors.New("layouts file is corrupt")
+
+func layoutsPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, historyDir, layoutsFile)
+}
+
+func LayoutKey(cfg Config, schema, table string) string {
+	conn := cfg.ID
+	if conn == "" {
+		conn = DisplayName(cfg)
+	}
+	return conn + "/" + cfg.DBName + "/" + schema + "." + table
+}
+
+func readLayouts() (map[string]ColumnLayout, error) {
+	data, err := os.ReadFile(layoutsPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]ColumnLayout{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var doc layoutsDoc
+	if err := json.Unmarshal(data, &doc); err != nil {
+		backup, berr := backupCorruptFile(layoutsPath(), data)
+		if berr != nil {
+			return nil, fmt.Errorf("%s: %v (backup failed: %v)", layoutsPath(), err, berr)
+		}
+		return nil, fmt.Errorf("%w: %v (backed up to %s)", ErrLayoutsCorrupt, err, backup)
+	}
+	if doc.Tables == nil {
+		doc.Tables = map[string]ColumnLayout{}
+	}
+	return doc.Tables, nil
+}
+
+func LoadColumnLayout(key string) (ColumnLayout, error) {
+	tables, err := readLayouts()
+	if err != nil {
+		return ColumnLayout{}, err
+	}
+	return tables[key], nil
+}
+
+func SaveColumnLayout(key string, layout ColumnLayout) error {
+	p := layoutsPath()
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return err
+	}
+	unlock, err := lockPath(p + ".lock")
+	if err != nil {
+		return fmt.Errorf("lock layouts: %w", err)
+	}
+	defer unlock()
+
+	tables, err := readLayouts()
+	if errors.Is(err, ErrLayoutsCorrupt) {
+		tables = map[string]ColumnLayout{}
+	} else if err != nil {
+		return err
+	}
+	if layout.IsZero() {
+		delete(tables, key)
+	} else {
+		tables[key] = layout
+	}
+	data, err := json.MarshalIndent(layoutsDoc{Version: 1, Tables: tables}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(p, data)
+}
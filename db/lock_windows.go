@@ -0,0 +1,38 @@
+//go:build windows
+
+package db
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+const lockTimeout = 5 * time.Second
+
+func lockPath(path string) (func(), error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	handle := windows.Handle(f.Fd())
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		ol := new(windows.Overlapped)
+		err := windows.LockFileEx(handle, windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, ol)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, windows.ERROR_LOCK_VIOLATION) || time.Now().After(deadline) {
+			f.Close()
+			return nil, err
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return func() {
+		_ = windows.UnlockFileEx(handle, 0, 1, 0, new(windows.Overlapped))
+		f.Close()
+	}, nil
+}
@@ -4,24 +4,175 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 )
 
 type mysqlDB struct {
-	conn *sql.DB
+	pool   *sql.DB
+	conn   *sql.Conn
+	format valueFormat
 }
 
-func newMysqlDB(dsn string) (*mysqlDB, error) {
-	conn, err := sql.Open("mysql", dsn)
+func newMysqlDB(ctx context.Context, dsn string, readOnly bool, format valueFormat) (*mysqlDB, error) {
+	pool, err := sql.Open("mysql", dsn)
 	if err != nil {
 		return nil, err
 	}
-	if err := conn.Ping(); err != nil {
+	conn, err := pool.Conn(ctx)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+	if err := conn.PingContext(ctx); err != nil {
 		conn.Close()
+		pool.Close()
+		return nil, err
+	}
+	if readOnly {
+		if _, err := conn.ExecContext(ctx, "SET SESSION TRANSACTION READ ONLY"); err != nil {
+			conn.Close()
+			pool.Close()
+			return nil, err
+		}
+	}
+	if format.loc != nil {
+		format.server = mysqlSessionZone(ctx, conn)
+	}
+	return &mysqlDB{pool: pool, conn: conn, format: format}, nil
+}
+
+// mysqlSessionZone falls back to the current UTC offset when Go has no zone by the server's name.
+func mysqlSessionZone(ctx context.Context, conn *sql.Conn) *time.Location {
+	var session, system string
+	var offset int
+	err := conn.QueryRowContext(ctx, "SELECT @@session.time_zone, @@system_time_zone, TIMESTAMPDIFF(SECOND, UTC_TIMESTAMP(), NOW())").
+		Scan(&session, &system, &offset)
+	if err != nil {
+		return time.UTC
+	}
+	name := session
+	if name == "SYSTEM" {
+		name = system
+	}
+	if loc, err := time.LoadLocation(name); err == nil && name != "" {
+		return loc
+	}
+	return time.FixedZone(name, offset)
+}
+
+func (d *mysqlDB) ListDatabases(ctx context.Context) ([]string, error) {
+	rows, err := d.conn.QueryContext(ctx, "SHOW DATABASES")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+func (d *mysqlDB) SwitchDatabase(ctx context.Context, name string) error {
+	_, err := d.conn.ExecContext(ctx, "USE "+quoteMySQLIdent(name))
+	return err
+}
+
+func (d *mysqlDB) ListSchemas(ctx context.Context) ([]string, error) {
+	query := `SELECT schema_name FROM information_schema.schemata
+	          WHERE (DATABASE() IS NOT NULL AND schema_name = DATABASE())
+	             OR (DATABASE() IS NULL AND schema_name NOT IN ('information_schema', 'mysql', 'performance_schema', 'sys'))
+	          ORDER BY schema_name`
+	rows, err := d.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schemas []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, name)
+	}
+	return schemas, rows.Err()
+}
+
+func (d *mysqlDB) listObjects(ctx context.Context, schema string, kind ObjectKind, query string) ([]Object, error) {
+	rows, err := d.conn.QueryContext(ctx, query, schema)
+	if err != nil {
 		return nil, err
 	}
-	return &mysqlDB{conn: conn}, nil
+	defer rows.Close()
+
+	var objects []Object
+	for rows.Next() {
+		o := Object{Schema: schema, Kind: kind}
+		if err := rows.Scan(&o.Name, &o.Detail); err != nil {
+			return nil, err
+		}
+		if kind == KindFunction {
+			if o.Detail == "PROCEDURE" {
+				o.Kind = KindProcedure
+			}
+			o.Detail = ""
+		}
+		objects = append(objects, o)
+	}
+	return objects, rows.Err()
+}
+
+func (d *mysqlDB) ListBaseTables(ctx context.Context, schema string) ([]Object, error) {
+	query := `SELECT table_name, '' FROM information_schema.tables
+	          WHERE table_schema = ? AND table_type = 'BASE TABLE'
+	          ORDER BY table_name`
+	return d.listObjects(ctx, schema, KindTable, query)
+}
+
+func (d *mysqlDB) ListViews(ctx context.Context, schema string) ([]Object, error) {
+	query := `SELECT table_name, '' FROM information_schema.views
+	          WHERE table_schema = ?
+	          ORDER BY table_name`
+	return d.listObjects(ctx, schema, KindView, query)
+}
+
+func (d *mysqlDB) ListMaterializedViews(_ context.Context, _ string) ([]Object, error) {
+	return nil, nil
+}
+
+func (d *mysqlDB) ListSchemaIndexes(ctx context.Context, schema string) ([]Object, error) {
+	query := `SELECT DISTINCT INDEX_NAME, TABLE_NAME FROM information_schema.STATISTICS
+	          WHERE TABLE_SCHEMA = ?
+	          ORDER BY INDEX_NAME, TABLE_NAME`
+	return d.listObjects(ctx, schema, KindIndex, query)
+}
+
+func (d *mysqlDB) ListSequences(_ context.Context, _ string) ([]Object, error) {
+	return nil, nil
+}
+
+func (d *mysqlDB) ListFunctions(ctx context.Context, schema string) ([]Object, error) {
+	query := `SELECT routine_name, routine_type FROM information_schema.routines
+	          WHERE routine_schema = ?
+	          ORDER BY routine_name`
+	return d.listObjects(ctx, schema, KindFunction, query)
+}
+
+func (d *mysqlDB) ListTriggers(ctx context.Context, schema string) ([]Object, error) {
+	query := `SELECT trigger_name, event_object_table FROM information_schema.triggers
+	          WHERE trigger_schema = ?
+	          ORDER BY trigger_name`
+	return d.listObjects(ctx, schema, KindTrigger, query)
 }
 
 func (d *mysqlDB) ListTables(ctx context.Context) ([]Table, error) {
@@ -43,13 +194,35 @@ func (d *mysqlDB) ListTables(ctx context.Context) ([]Table, error) {
 	return tables, rows.Err()
 }
 
+const mysqlColumnsQuery = `SELECT TABLE_SCHEMA, TABLE_NAME, COLUMN_NAME, COLUMN_TYPE,
+	       IS_NULLABLE = 'YES', COALESCE(COLUMN_DEFAULT, ''), ORDINAL_POSITION,
+	       COLUMN_KEY = 'PRI', COLUMN_COMMENT
+	FROM INFORMATION_SCHEMA.COLUMNS`
+
 func (d *mysqlDB) ListColumns(ctx context.Context) ([]Column, error) {
-	query := `SELECT TABLE_SCHEMA, TABLE_NAME, COLUMN_NAME
-	          FROM INFORMATION_SCHEMA.COLUMNS
-	          WHERE (DATABASE() IS NOT NULL AND TABLE_SCHEMA = DATABASE())
-	             OR (DATABASE() IS NULL AND TABLE_SCHEMA NOT IN ('information_schema','mysql','performance_schema','sys'))
-	          ORDER BY TABLE_NAME, ORDINAL_POSITION`
-	rows, err := d.conn.QueryContext(ctx, query)
+	query := mysqlColumnsQuery + `
+	WHERE (DATABASE() IS NOT NULL AND TABLE_SCHEMA = DATABASE())
+	   OR (DATABASE() IS NULL AND TABLE_SCHEMA NOT IN ('information_schema','mysql','performance_schema','sys'))
+	ORDER BY TABLE_SCHEMA, TABLE_NAME, ORDINAL_POSITION`
+	return d.queryColumns(ctx, query)
+}
+
+func (d *mysqlDB) ListSchemaColumns(ctx context.Context, schema string) ([]Column, error) {
+	query := mysqlColumnsQuery + `
+	WHERE TABLE_SCHEMA = ?
+	ORDER BY TABLE_NAME, ORDINAL_POSITION`
+	return d.queryColumns(ctx, query, schema)
+}
+
+func (d *mysqlDB) ListTableColumns(ctx context.Context, schema, table string) ([]Column, error) {
+	query := mysqlColumnsQuery + `
+	WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+	ORDER BY ORDINAL_POSITION`
+	return d.queryColumns(ctx, query, schema, table)
+}
+
+func (d *mysqlDB) queryColumns(ctx context.Context, query string, args ...any) ([]Column, error) {
+	rows, err := d.conn.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +230,7 @@ func (d *mysqlDB) ListColumns(ctx context.Context) ([]Column, error) {
 	var cols []Column
 	for rows.Next() {
 		var c Column
-		if err := rows.Scan(&c.Schema, &c.Table, &c.Name); err != nil {
+		if err := rows.Scan(&c.Schema, &c.Table, &c.Name, &c.DataType, &c.Nullable, &c.Default, &c.Position, &c.PrimaryKey, &c.Comment); err != nil {
 			return nil, err
 		}
 		cols = append(cols, c)
@@ -65,66 +238,387 @@ func (d *mysqlDB) ListColumns(ctx context.Context) ([]Column, error) {
 	return cols, rows.Err()
 }
 
-func (d *mysqlDB) FetchTableData(ctx context.Context, schema, table string, limit, offset int, sort *SortOption) (*QueryResult, error) {
-	query := fmt.Sprintf("SELECT * FROM %s.%s", quoteMySQLIdent(schema), quoteMySQLIdent(table))
-	if sort != nil && sort.Column != "" {
-		direction := "ASC"
-		if sort.Desc {
-			direction = "DESC"
+func (d *mysqlDB) ListIndexes(ctx context.Context, schema, table string) ([]Index, error) {
+	query := `SELECT INDEX_NAME, GROUP_CONCAT(COLUMN_NAME ORDER BY SEQ_IN_INDEX SEPARATOR ','),
+	                 MIN(NON_UNIQUE) = 0, INDEX_NAME = 'PRIMARY', MIN(INDEX_TYPE)
+	          FROM INFORMATION_SCHEMA.STATISTICS
+	          WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+	          GROUP BY INDEX_NAME
+	          ORDER BY INDEX_NAME = 'PRIMARY' DESC, INDEX_NAME`
+	rows, err := d.conn.QueryContext(ctx, query, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var indexes []Index
+	for rows.Next() {
+		var (
+			ix        Index
+			cols      sql.NullString
+			indexType string
+		)
+		if err := rows.Scan(&ix.Name, &cols, &ix.Unique, &ix.Primary, &indexType); err != nil {
+			return nil, err
 		}
-		query += fmt.Sprintf(" ORDER BY %s %s", quoteMySQLIdent(sort.Column), direction)
+		ix.Columns = splitList(cols.String)
+		ix.Size = -1
+		kind := "INDEX"
+		switch {
+		case ix.Primary:
+			kind = "PRIMARY KEY"
+		case ix.Unique:
+			kind = "UNIQUE INDEX"
+		}
+		ix.Definition = fmt.Sprintf("%s %s USING %s (%s)", kind, quoteMySQLIdent(ix.Name), indexType, quoteMySQLIdents(ix.Columns))
+		indexes = append(indexes, ix)
 	}
-	query += " LIMIT ? OFFSET ?"
-	rows, err := d.conn.QueryContext(ctx, query, limit, offset)
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	d.fillIndexSizes(ctx, schema, table, indexes)
+	return indexes, nil
+}
+
+func (d *mysqlDB) fillIndexSizes(ctx context.Context, schema, table string, indexes []Index) {
+	query := `SELECT index_name, stat_value * @@innodb_page_size
+	          FROM mysql.innodb_index_stats
+	          WHERE database_name = ? AND table_name = ? AND stat_name = 'size'`
+	rows, err := d.conn.QueryContext(ctx, query, schema, table)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			name string
+			size int64
+		)
+		if rows.Scan(&name, &size) != nil {
+			return
+		}
+		for i := range indexes {
+			if indexes[i].Name == name {
+				indexes[i].Size = size
+			}
+		}
+	}
+}
+
+func quoteMySQLIdents(idents []string) string {
+	quoted := make([]string, len(idents))
+	for i, ident := range idents {
+		quoted[i] = quoteMySQLIdent(ident)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func (d *mysqlDB) ListConstraints(ctx context.Context, schema, table string) ([]Constraint, error) {
+	query := `SELECT tc.CONSTRAINT_NAME, tc.CONSTRAINT_TYPE,
+	                 GROUP_CONCAT(k.COLUMN_NAME ORDER BY k.ORDINAL_POSITION SEPARATOR ',')
+	          FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+	          LEFT JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE k
+	            ON k.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA AND k.TABLE_NAME = tc.TABLE_NAME AND k.CONSTRAINT_NAME = tc.CONSTRAINT_NAME
+	          WHERE tc.TABLE_SCHEMA = ? AND tc.TABLE_NAME = ? AND tc.CONSTRAINT_TYPE IN ('PRIMARY KEY', 'UNIQUE', 'CHECK')
+	          GROUP BY tc.CONSTRAINT_NAME, tc.CONSTRAINT_TYPE
+	          ORDER BY FIELD(tc.CONSTRAINT_TYPE, 'PRIMARY KEY', 'UNIQUE', 'CHECK'), tc.CONSTRAINT_NAME`
+	rows, err := d.conn.QueryContext(ctx, query, schema, table)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	columns, err := rows.Columns()
+	var constraints []Constraint
+	for rows.Next() {
+		var (
+			c    Constraint
+			cols sql.NullString
+		)
+		if err := rows.Scan(&c.Name, &c.Kind, &cols); err != nil {
+			return nil, err
+		}
+		c.Columns = splitList(cols.String)
+		if c.Kind != ConstraintCheck {
+			c.Definition = fmt.Sprintf("%s (%s)", c.Kind, quoteMySQLIdents(c.Columns))
+		}
+		constraints = append(constraints, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	d.fillCheckClauses(ctx, schema, constraints)
+	return constraints, nil
+}
+
+func (d *mysqlDB) fillCheckClauses(ctx context.Context, schema string, constraints []Constraint) {
+	query := `SELECT CONSTRAINT_NAME, CHECK_CLAUSE FROM INFORMATION_SCHEMA.CHECK_CONSTRAINTS WHERE CONSTRAINT_SCHEMA = ?`
+	rows, err := d.conn.QueryContext(ctx, query, schema)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name, clause string
+		if rows.Scan(&name, &clause) != nil {
+			return
+		}
+		for i := range constraints {
+			if constraints[i].Kind == ConstraintCheck && constraints[i].Name == name {
+				constraints[i].Definition = "CHECK (" + clause + ")"
+			}
+		}
+	}
+}
+
+const mysqlForeignKeysQuery = `SELECT k.CONSTRAINT_NAME, k.TABLE_SCHEMA, k.TABLE_NAME,
+	       GROUP_CONCAT(k.COLUMN_NAME ORDER BY k.ORDINAL_POSITION SEPARATOR ','),
+	       k.REFERENCED_TABLE_SCHEMA, k.REFERENCED_TABLE_NAME,
+	       GROUP_CONCAT(k.REFERENCED_COLUMN_NAME ORDER BY k.ORDINAL_POSITION SEPARATOR ','),
+	       r.UPDATE_RULE, r.DELETE_RULE
+	FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE k
+	JOIN INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS r
+	  ON r.CONSTRAINT_SCHEMA = k.CONSTRAINT_SCHEMA AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME AND r.TABLE_NAME = k.TABLE_NAME
+	WHERE k.REFERENCED_TABLE_NAME IS NOT NULL`
+
+func (d *mysqlDB) listForeignKeys(ctx context.Context, filter string, args ...any) ([]ForeignKey, error) {
+	query := mysqlForeignKeysQuery + " AND " + filter + `
+	GROUP BY k.CONSTRAINT_NAME, k.TABLE_SCHEMA, k.TABLE_NAME, k.REFERENCED_TABLE_SCHEMA, k.REFERENCED_TABLE_NAME, r.UPDATE_RULE, r.DELETE_RULE
+	ORDER BY k.TABLE_NAME, k.CONSTRAINT_NAME`
+	rows, err := d.conn.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	var resultRows [][]string
+	var fks []ForeignKey
 	for rows.Next() {
-		values := make([]sql.NullString, len(columns))
-		ptrs := make([]any, len(columns))
-		for i := range values {
-			ptrs[i] = &values[i]
+		var (
+			fk            ForeignKey
+			cols, refCols string
+		)
+		if err := rows.Scan(&fk.Name, &fk.Schema, &fk.Table, &cols, &fk.RefSchema, &fk.RefTable, &refCols, &fk.OnUpdate, &fk.OnDelete); err != nil {
+			return nil, err
 		}
-		if err := rows.Scan(ptrs...); err != nil {
+		fk.Columns = splitList(cols)
+		fk.RefColumns = splitList(refCols)
+		fks = append(fks, fk)
+	}
+	return fks, rows.Err()
+}
+
+func (d *mysqlDB) ListForeignKeys(ctx context.Context, schema, table string) ([]ForeignKey, error) {
+	return d.listForeignKeys(ctx, "k.TABLE_SCHEMA = ? AND k.TABLE_NAME = ?", schema, table)
+}
+
+func (d *mysqlDB) ListReferencingKeys(ctx context.Context, schema, table string) ([]ForeignKey, error) {
+	return d.listForeignKeys(ctx, "k.REFERENCED_TABLE_SCHEMA = ? AND k.REFERENCED_TABLE_NAME = ?", schema, table)
+}
+
+func (d *mysqlDB) ListSchemaForeignKeys(ctx context.Context, schema string) ([]ForeignKey, error) {
+	return d.listForeignKeys(ctx, "k.TABLE_SCHEMA = ?", schema)
+}
+
+func (d *mysqlDB) ListTableTriggers(ctx context.Context, schema, table string) ([]Trigger, error) {
+	query := `SELECT TRIGGER_NAME, ACTION_TIMING, EVENT_MANIPULATION, ACTION_STATEMENT
+	          FROM INFORMATION_SCHEMA.TRIGGERS
+	          WHERE EVENT_OBJECT_SCHEMA = ? AND EVENT_OBJECT_TABLE = ?
+	          ORDER BY TRIGGER_NAME`
+	rows, err := d.conn.QueryContext(ctx, query, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var triggers []Trigger
+	for rows.Next() {
+		var t Trigger
+		if err := rows.Scan(&t.Name, &t.Timing, &t.Events, &t.Definition); err != nil {
 			return nil, err
 		}
-		row := make([]string, len(columns))
-		for i, v := range values {
-			if v.Valid {
-				row[i] = v.String
-			} else {
-				row[i] = "NULL"
+		triggers = append(triggers, t)
+	}
+	return triggers, rows.Err()
+}
+
+func (d *mysqlDB) ObjectDDL(ctx context.Context, o Object) (string, error) {
+	name := quoteMySQLIdent(o.Schema) + "." + quoteMySQLIdent(o.Name)
+	switch o.Kind {
+	case KindTable:
+		return d.showCreate(ctx, "SHOW CREATE TABLE "+name, "Create Table")
+	case KindView:
+		return d.showCreate(ctx, "SHOW CREATE VIEW "+name, "Create View")
+	case KindFunction:
+		return d.showCreate(ctx, "SHOW CREATE FUNCTION "+name, "Create Function")
+	case KindProcedure:
+		return d.showCreate(ctx, "SHOW CREATE PROCEDURE "+name, "Create Procedure")
+	case KindTrigger:
+		return d.showCreate(ctx, "SHOW CREATE TRIGGER "+name, "SQL Original Statement")
+	case KindIndex:
+		return d.indexDDL(ctx, o)
+	}
+	return "", fmt.Errorf("no DDL for %s", o.Kind)
+}
+
+func (d *mysqlDB) showCreate(ctx context.Context, query, column string) (string, error) {
+	rows, err := d.conn.QueryContext(ctx, query)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return "", err
+		}
+		return "", sql.ErrNoRows
+	}
+	values := make([]sql.NullString, len(columns))
+	ptrs := make([]any, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return "", err
+	}
+	for i, c := range columns {
+		if strings.EqualFold(c, column) {
+			if !values[i].Valid {
+				return "", fmt.Errorf("%s is not visible to the current user", strings.ToLower(column))
 			}
+			return values[i].String + ";", nil
 		}
-		resultRows = append(resultRows, row)
 	}
+	return "", fmt.Errorf("unexpected result from %s", query)
+}
+
+func (d *mysqlDB) indexDDL(ctx context.Context, o Object) (string, error) {
+	indexes, err := d.ListIndexes(ctx, o.Schema, o.Detail)
+	if err != nil {
+		return "", err
+	}
+	table := quoteMySQLIdent(o.Schema) + "." + quoteMySQLIdent(o.Detail)
+	for _, ix := range indexes {
+		if ix.Name != o.Name {
+			continue
+		}
+		if ix.Primary {
+			return fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s);", table, quoteMySQLIdents(ix.Columns)), nil
+		}
+		unique := ""
+		if ix.Unique {
+			unique = "UNIQUE "
+		}
+		return fmt.Sprintf("CREATE %sINDEX %s ON %s (%s);", unique, quoteMySQLIdent(ix.Name), table, quoteMySQLIdents(ix.Columns)), nil
+	}
+	return "", fmt.Errorf("index %s not found on %s", o.Name, o.Detail)
+}
 
-	return &QueryResult{Columns: columns, Rows: resultRows}, rows.Err()
+func mysqlWhere(filters []ColumnFilter) (string, []any) {
+	if len(filters) == 0 {
+		return "", nil
+	}
+	conds := make([]string, len(filters))
+	args := make([]any, len(filters))
+	for i, f := range filters {
+		conds[i] = quoteMySQLIdent(f.Column) + " = ?"
+		args[i] = f.Value
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
 }
 
-func (d *mysqlDB) ExecQuery(ctx context.Context, query string) (*QueryResult, error) {
-	rows, err := d.conn.QueryContext(ctx, query)
+func (d *mysqlDB) CountRows(ctx context.Context, schema, table string, filters []ColumnFilter) (int64, error) {
+	where, args := mysqlWhere(filters)
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s.%s", quoteMySQLIdent(schema), quoteMySQLIdent(table)) + where
+	var n int64
+	err := d.conn.QueryRowContext(ctx, query, args...).Scan(&n)
+	return n, err
+}
+
+func (d *mysqlDB) FetchTableData(ctx context.Context, schema, table string, limit, offset int, sort *SortOption, filters []ColumnFilter) (*QueryResult, error) {
+	where, args := mysqlWhere(filters)
+	query := fmt.Sprintf("SELECT * FROM %s.%s", quoteMySQLIdent(schema), quoteMySQLIdent(table)) + where
+	if sort != nil && sort.Column != "" {
+		direction := "ASC"
+		if sort.Desc {
+			direction = "DESC"
+		}
+		query += fmt.Sprintf(" ORDER BY %s %s", quoteMySQLIdent(sort.Column), direction)
+	}
+	query += " LIMIT ? OFFSET ?"
+	rows, err := d.conn.QueryContext(ctx, query, append(args, limit, offset)...)
 	if err != nil {
 		return nil, err
 	}
+	return d.collectRows(rows)
+}
+
+func (d *mysqlDB) ProfileColumn(ctx context.Context, schema, table, column string, filters []ColumnFilter) (*ColumnProfile, error) {
+	where, args := mysqlWhere(filters)
+	return profileColumn(ctx, d, schema, table, column, func(bool) profileSQL {
+		col := quoteMySQLIdent(column)
+		q := profileSQL{
+			rel:    quoteMySQLIdent(schema) + "." + quoteMySQLIdent(table),
+			col:    col,
+			value:  col,
+			length: "CHAR_LENGTH(" + col + ")",
+			number: col,
+			query: func(ctx context.Context, sql string) (*QueryResult, error) {
+				rows, err := d.conn.QueryContext(ctx, sql, args...)
+				if err != nil {
+					return nil, err
+				}
+				return d.collectRows(rows)
+			},
+		}
+		if where != "" {
+			q.where = []string{strings.TrimPrefix(where, " WHERE ")}
+		}
+		return q
+	})
+}
+
+func (d *mysqlDB) searchTable(ctx context.Context, t SearchTarget, value string, limit int) (*QueryResult, error) {
+	names := make([]string, len(t.columns))
+	conds := make([]string, len(t.columns))
+	var args []any
+	for i, c := range t.columns {
+		names[i] = quoteMySQLIdent(c.name)
+		if c.exact {
+			conds[i] = names[i] + " = ?"
+			args = append(args, value)
+		} else {
+			conds[i] = names[i] + " LIKE ?"
+			args = append(args, likePattern(value))
+		}
+	}
+	query := fmt.Sprintf("SELECT %s, %s FROM %s.%s WHERE %s LIMIT %d", strings.Join(names, ", "), strings.Join(conds, ", "),
+		quoteMySQLIdent(t.Schema), quoteMySQLIdent(t.Table), strings.Join(conds, " OR "), limit)
+	rows, err := d.conn.QueryContext(ctx, query, append(args, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	return d.collectRows(rows)
+}
+
+func (d *mysqlDB) collectRows(rows *sql.Rows) (*QueryResult, error) {
 	defer rows.Close()
 
-	columns, err := rows.Columns()
+	types, err := rows.ColumnTypes()
 	if err != nil {
 		return nil, err
 	}
+	columns := make([]string, len(types))
+	for i, t := range types {
+		columns[i] = t.Name()
+	}
 
-	var resultRows [][]string
+	result := &QueryResult{Columns: columns}
 	for rows.Next() {
-		values := make([]sql.NullString, len(columns))
+		values := make([]any, len(columns))
 		ptrs := make([]any, len(columns))
 		for i := range values {
 			ptrs[i] = &values[i]
@@ -133,19 +627,57 @@ func (d *mysqlDB) ExecQuery(ctx context.Context, query string) (*QueryResult, er
 			return nil, err
 		}
 		row := make([]string, len(columns))
+		nulls := make([]bool, len(columns))
+		var rawRow []string
+		if d.format != (valueFormat{}) {
+			rawRow = make([]string, len(columns))
+		}
 		for i, v := range values {
-			if v.Valid {
-				row[i] = v.String
-			} else {
-				row[i] = "NULL"
+			row[i] = d.format.mysqlValue(types[i].DatabaseTypeName(), v)
+			nulls[i] = v == nil
+			if rawRow != nil {
+				rawRow[i] = valueFormat{}.mysqlValue(types[i].DatabaseTypeName(), v)
 			}
 		}
-		resultRows = append(resultRows, row)
+		result.Rows = append(result.Rows, row)
+		result.Nulls = append(result.Nulls, nulls)
+		if rawRow != nil {
+			result.Raw = append(result.Raw, rawRow)
+		}
 	}
 
-	return &QueryResult{Columns: columns, Rows: resultRows}, rows.Err()
+	return result, rows.Err()
+}
+
+func (d *mysqlDB) ExecQuery(ctx context.Context, query string) (*QueryResult, error) {
+	rows, err := d.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return d.collectRows(rows)
+}
+
+func (d *mysqlDB) Ping(ctx context.Context) error {
+	return d.conn.PingContext(ctx)
+}
+
+func (d *mysqlDB) captureSession(ctx context.Context) (sessionState, error) {
+	var database sql.NullString
+	if err := d.conn.QueryRowContext(ctx, "SELECT DATABASE()").Scan(&database); err != nil {
+		return sessionState{}, err
+	}
+	return sessionState{database: database.String}, nil
+}
+
+func (d *mysqlDB) restoreSession(ctx context.Context, s sessionState) error {
+	if s.database == "" {
+		return nil
+	}
+	_, err := d.conn.ExecContext(ctx, "USE "+quoteMySQLIdent(s.database))
+	return err
 }
 
 func (d *mysqlDB) Close(_ context.Context) error {
-	return d.conn.Close()
+	d.conn.Close()
+	return d.pool.Close()
 }
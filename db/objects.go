@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+type ObjectKind string
+
+const (
+	KindTable            ObjectKind = "table"
+	KindView             ObjectKind = "view"
+	KindMaterializedView ObjectKind = "materialized view"
+	KindIndex            ObjectKind = "index"
+	KindSequence         ObjectKind = "sequence"
+	KindFunction         ObjectKind = "function"
+	KindProcedure        ObjectKind = "procedure"
+	KindTrigger          ObjectKind = "trigger"
+)
+
+type Object struct {
+	Schema string
+	Name   string
+	Kind   ObjectKind
+	Detail string
+}
+
+func (k ObjectKind) HasRows() bool {
+	return k == KindTable || k == KindView || k == KindMaterializedView
+}
+
+func ObjectKinds(driver Driver) []ObjectKind {
+	if driver == DriverMySQL {
+		return []ObjectKind{KindTable, KindView, KindIndex, KindFunction, KindTrigger}
+	}
+	return []ObjectKind{KindTable, KindView, KindMaterializedView, KindIndex, KindSequence, KindFunction, KindTrigger}
+}
+
+func ListObjects(ctx context.Context, d DB, schema string, kind ObjectKind) ([]Object, error) {
+	switch kind {
+	case KindTable:
+		return d.ListBaseTables(ctx, schema)
+	case KindView:
+		return d.ListViews(ctx, schema)
+	case KindMaterializedView:
+		return d.ListMaterializedViews(ctx, schema)
+	case KindIndex:
+		return d.ListSchemaIndexes(ctx, schema)
+	case KindSequence:
+		return d.ListSequences(ctx, schema)
+	case KindFunction, KindProcedure:
+		return d.ListFunctions(ctx, schema)
+	case KindTrigger:
+		return d.ListTriggers(ctx, schema)
+	}
+	return nil, fmt.Errorf("unknown object kind %q", kind)
+}
@@ -3,20 +3,155 @@ package db
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 )
 
 type pgxDB struct {
-	conn *pgx.Conn
+	conn     *pgx.Conn
+	readOnly bool
+	format   valueFormat
 }
 
-func newPgxDB(ctx context.Context, dsn string) (*pgxDB, error) {
-	conn, err := pgx.Connect(ctx, dsn)
+func newPgxDB(ctx context.Context, dsn string, readOnly bool, format valueFormat) (*pgxDB, error) {
+	cc, err := pgx.ParseConfig(dsn)
 	if err != nil {
 		return nil, err
 	}
-	return &pgxDB{conn: conn}, nil
+	conn, err := connectPgx(ctx, cc, readOnly)
+	if err != nil {
+		return nil, err
+	}
+	if format.loc == nil {
+		format.loc = time.Local
+		var name string
+		if conn.QueryRow(ctx, "SELECT current_setting('TimeZone')").Scan(&name) == nil {
+			if loc, err := time.LoadLocation(name); err == nil {
+				format.loc = loc
+			}
+		}
+	}
+	return &pgxDB{conn: conn, readOnly: readOnly, format: format}, nil
+}
+
+func connectPgx(ctx context.Context, cc *pgx.ConnConfig, readOnly bool) (*pgx.Conn, error) {
+	conn, err := pgx.ConnectConfig(ctx, cc)
+	if err != nil {
+		return nil, err
+	}
+	if readOnly {
+		if _, err := conn.Exec(ctx, "SET SESSION CHARACTERISTICS AS TRANSACTION READ ONLY"); err != nil {
+			conn.Close(ctx)
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+func (d *pgxDB) ListDatabases(ctx context.Context) ([]string, error) {
+	query := `SELECT datname FROM pg_database WHERE datallowconn AND NOT datistemplate ORDER BY datname`
+	rows, err := d.conn.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+func (d *pgxDB) SwitchDatabase(ctx context.Context, name string) error {
+	cc := d.conn.Config().Copy()
+	cc.Database = name
+	conn, err := connectPgx(ctx, cc, d.readOnly)
+	if err != nil {
+		return err
+	}
+	d.conn.Close(ctx)
+	d.conn = conn
+	return nil
+}
+
+func (d *pgxDB) ListSchemas(ctx context.Context) ([]string, error) {
+	query := `SELECT nspname FROM pg_namespace
+	          WHERE nspname NOT IN ('pg_catalog', 'information_schema')
+	            AND nspname NOT LIKE 'pg\_toast%' AND nspname NOT LIKE 'pg\_temp\_%'
+	          ORDER BY nspname`
+	rows, err := d.conn.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+func (d *pgxDB) listObjects(ctx context.Context, schema string, kind ObjectKind, query string) ([]Object, error) {
+	rows, err := d.conn.Query(ctx, query, schema)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (Object, error) {
+		o := Object{Schema: schema, Kind: kind}
+		err := row.Scan(&o.Name, &o.Detail)
+		if kind == KindFunction {
+			if o.Detail == "p" {
+				o.Kind = KindProcedure
+			}
+			o.Detail = ""
+		}
+		return o, err
+	})
+}
+
+func pgRelations(relkinds string) string {
+	return `SELECT c.relname, '' FROM pg_class c
+	        JOIN pg_namespace n ON n.oid = c.relnamespace
+	        WHERE n.nspname = $1 AND c.relkind IN (` + relkinds + `)
+	        ORDER BY c.relname`
+}
+
+func (d *pgxDB) ListBaseTables(ctx context.Context, schema string) ([]Object, error) {
+	return d.listObjects(ctx, schema, KindTable, pgRelations("'r', 'p', 'f'"))
+}
+
+func (d *pgxDB) ListViews(ctx context.Context, schema string) ([]Object, error) {
+	return d.listObjects(ctx, schema, KindView, pgRelations("'v'"))
+}
+
+func (d *pgxDB) ListMaterializedViews(ctx context.Context, schema string) ([]Object, error) {
+	return d.listObjects(ctx, schema, KindMaterializedView, pgRelations("'m'"))
+}
+
+func (d *pgxDB) ListSchemaIndexes(ctx context.Context, schema string) ([]Object, error) {
+	query := `SELECT i.relname, t.relname
+	          FROM pg_index ix
+	          JOIN pg_class i ON i.oid = ix.indexrelid
+	          JOIN pg_class t ON t.oid = ix.indrelid
+	          JOIN pg_namespace n ON n.oid = i.relnamespace
+	          WHERE n.nspname = $1
+	          ORDER BY i.relname`
+	return d.listObjects(ctx, schema, KindIndex, query)
+}
+
+func (d *pgxDB) ListSequences(ctx context.Context, schema string) ([]Object, error) {
+	return d.listObjects(ctx, schema, KindSequence, pgRelations("'S'"))
+}
+
+func (d *pgxDB) ListFunctions(ctx context.Context, schema string) ([]Object, error) {
+	query := `SELECT p.proname || '(' || pg_get_function_identity_arguments(p.oid) || ')', p.prokind::text
+	          FROM pg_proc p
+	          JOIN pg_namespace n ON n.oid = p.pronamespace
+	          WHERE n.nspname = $1 AND p.prokind IN ('f', 'p')
+	          ORDER BY 1`
+	return d.listObjects(ctx, schema, KindFunction, query)
+}
+
+func (d *pgxDB) ListTriggers(ctx context.Context, schema string) ([]Object, error) {
+	query := `SELECT t.tgname, c.relname
+	          FROM pg_trigger t
+	          JOIN pg_class c ON c.oid = t.tgrelid
+	          JOIN pg_namespace n ON n.oid = c.relnamespace
+	          WHERE n.nspname = $1 AND NOT t.tgisinternal
+	          ORDER BY t.tgname`
+	return d.listObjects(ctx, schema, KindTrigger, query)
 }
 
 func (d *pgxDB) ListTables(ctx context.Context) ([]Table, error) {
@@ -34,24 +169,290 @@ func (d *pgxDB) ListTables(ctx context.Context) ([]Table, error) {
 	return table, nil
 }
 
+const pgColumnsQuery = `SELECT n.nspname, c.relname, a.attname,
+	       format_type(a.atttypid, a.atttypmod),
+	       NOT a.attnotnull,
+	       COALESCE(pg_get_expr(ad.adbin, ad.adrelid), ''),
+	       a.attnum::int,
+	       EXISTS (SELECT 1 FROM pg_index i WHERE i.indrelid = c.oid AND i.indisprimary AND a.attnum = ANY(i.indkey)),
+	       COALESCE(col_description(c.oid, a.attnum), '')
+	FROM pg_attribute a
+	JOIN pg_class c ON c.oid = a.attrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	LEFT JOIN pg_attrdef ad ON ad.adrelid = a.attrelid AND ad.adnum = a.attnum
+	WHERE a.attnum > 0 AND NOT a.attisdropped AND c.relkind IN ('r', 'p', 'f', 'v', 'm')`
+
 func (d *pgxDB) ListColumns(ctx context.Context) ([]Column, error) {
-	query := `SELECT table_schema, table_name, column_name
-	          FROM information_schema.columns
-	          WHERE table_schema NOT IN ('pg_catalog', 'information_schema')
-	          ORDER BY table_schema, table_name, ordinal_position`
+	query := pgColumnsQuery + `
+	  AND n.nspname NOT IN ('pg_catalog', 'information_schema') AND n.nspname NOT LIKE 'pg\_toast%'
+	ORDER BY n.nspname, c.relname, a.attnum`
 	rows, err := d.conn.Query(ctx, query)
 	if err != nil {
 		return nil, err
 	}
-	cols, err := pgx.CollectRows(rows, pgx.RowToStructByPos[Column])
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[Column])
+}
+
+func (d *pgxDB) ListSchemaColumns(ctx context.Context, schema string) ([]Column, error) {
+	query := pgColumnsQuery + `
+	  AND n.nspname = $1
+	ORDER BY c.relname, a.attnum`
+	rows, err := d.conn.Query(ctx, query, schema)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[Column])
+}
+
+func (d *pgxDB) ListTableColumns(ctx context.Context, schema, table string) ([]Column, error) {
+	query := pgColumnsQuery + `
+	  AND n.nspname = $1 AND c.relname = $2
+	ORDER BY a.attnum`
+	rows, err := d.conn.Query(ctx, query, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[Column])
+}
+
+func pgAttnames(rel, keys string) string {
+	return `ARRAY(SELECT a.attname::text FROM unnest(` + keys + `) WITH ORDINALITY k(attnum, ord)
+	        JOIN pg_attribute a ON a.attrelid = ` + rel + ` AND a.attnum = k.attnum ORDER BY k.ord)`
+}
+
+func (d *pgxDB) ListIndexes(ctx context.Context, schema, table string) ([]Index, error) {
+	query := `SELECT i.relname, ` + pgAttnames("ix.indrelid", "ix.indkey") + `,
+	                 ix.indisunique, ix.indisprimary, pg_get_indexdef(ix.indexrelid), pg_relation_size(ix.indexrelid)
+	          FROM pg_index ix
+	          JOIN pg_class i ON i.oid = ix.indexrelid
+	          JOIN pg_class t ON t.oid = ix.indrelid
+	          JOIN pg_namespace n ON n.oid = t.relnamespace
+	          WHERE n.nspname = $1 AND t.relname = $2
+	          ORDER BY ix.indisprimary DESC, i.relname`
+	rows, err := d.conn.Query(ctx, query, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[Index])
+}
+
+func (d *pgxDB) ListConstraints(ctx context.Context, schema, table string) ([]Constraint, error) {
+	query := `SELECT con.conname,
+	                 CASE con.contype WHEN 'p' THEN 'PRIMARY KEY' WHEN 'u' THEN 'UNIQUE' ELSE 'CHECK' END,
+	                 ` + pgAttnames("con.conrelid", "con.conkey") + `,
+	                 pg_get_constraintdef(con.oid)
+	          FROM pg_constraint con
+	          JOIN pg_class t ON t.oid = con.conrelid
+	          JOIN pg_namespace n ON n.oid = t.relnamespace
+	          WHERE n.nspname = $1 AND t.relname = $2 AND con.contype IN ('p', 'u', 'c')
+	          ORDER BY position(con.contype::text IN 'puc'), con.conname`
+	rows, err := d.conn.Query(ctx, query, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[Constraint])
+}
+
+var pgForeignKeysQuery = `SELECT con.conname, sn.nspname, st.relname, ` + pgAttnames("con.conrelid", "con.conkey") + `,
+	       tn.nspname, tt.relname, ` + pgAttnames("con.confrelid", "con.confkey") + `,
+	       ` + pgFKAction("con.confupdtype") + `, ` + pgFKAction("con.confdeltype") + `
+	FROM pg_constraint con
+	JOIN pg_class st ON st.oid = con.conrelid
+	JOIN pg_namespace sn ON sn.oid = st.relnamespace
+	JOIN pg_class tt ON tt.oid = con.confrelid
+	JOIN pg_namespace tn ON tn.oid = tt.relnamespace
+	WHERE con.contype = 'f'`
+
+func pgFKAction(col string) string {
+	return `CASE ` + col + ` WHEN 'r' THEN 'RESTRICT' WHEN 'c' THEN 'CASCADE' WHEN 'n' THEN 'SET NULL' WHEN 'd' THEN 'SET DEFAULT' ELSE 'NO ACTION' END`
+}
+
+func (d *pgxDB) listForeignKeys(ctx context.Context, filter string, args ...any) ([]ForeignKey, error) {
+	rows, err := d.conn.Query(ctx, pgForeignKeysQuery+" AND "+filter+" ORDER BY st.relname, con.conname", args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[ForeignKey])
+}
+
+func (d *pgxDB) ListForeignKeys(ctx context.Context, schema, table string) ([]ForeignKey, error) {
+	return d.listForeignKeys(ctx, "sn.nspname = $1 AND st.relname = $2", schema, table)
+}
+
+func (d *pgxDB) ListReferencingKeys(ctx context.Context, schema, table string) ([]ForeignKey, error) {
+	return d.listForeignKeys(ctx, "tn.nspname = $1 AND tt.relname = $2", schema, table)
+}
+
+func (d *pgxDB) ListSchemaForeignKeys(ctx context.Context, schema string) ([]ForeignKey, error) {
+	return d.listForeignKeys(ctx, "sn.nspname = $1", schema)
+}
+
+func (d *pgxDB) ListTableTriggers(ctx context.Context, schema, table string) ([]Trigger, error) {
+	query := `SELECT t.tgname,
+	                 CASE WHEN t.tgtype::int & 2 <> 0 THEN 'BEFORE' WHEN t.tgtype::int & 64 <> 0 THEN 'INSTEAD OF' ELSE 'AFTER' END,
+	                 concat_ws(' OR ',
+	                     CASE WHEN t.tgtype::int & 4 <> 0 THEN 'INSERT' END,
+	                     CASE WHEN t.tgtype::int & 16 <> 0 THEN 'UPDATE' END,
+	                     CASE WHEN t.tgtype::int & 8 <> 0 THEN 'DELETE' END,
+	                     CASE WHEN t.tgtype::int & 32 <> 0 THEN 'TRUNCATE' END),
+	                 pg_get_triggerdef(t.oid)
+	          FROM pg_trigger t
+	          JOIN pg_class c ON c.oid = t.tgrelid
+	          JOIN pg_namespace n ON n.oid = c.relnamespace
+	          WHERE n.nspname = $1 AND c.relname = $2 AND NOT t.tgisinternal
+	          ORDER BY t.tgname`
+	rows, err := d.conn.Query(ctx, query, schema, table)
 	if err != nil {
 		return nil, err
 	}
-	return cols, nil
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[Trigger])
+}
+
+func (d *pgxDB) ObjectDDL(ctx context.Context, o Object) (string, error) {
+	rel := quotePostgresIdent(o.Schema) + "." + quotePostgresIdent(o.Name)
+	var (
+		query string
+		args  []any
+	)
+	switch o.Kind {
+	case KindTable:
+		return d.tableDDL(ctx, o.Schema, o.Name)
+	case KindView:
+		query = `SELECT 'CREATE OR REPLACE VIEW ' || $1::text || E' AS\n' || pg_get_viewdef($1::text::regclass, true)`
+		args = []any{rel}
+	case KindMaterializedView:
+		query = `SELECT 'CREATE MATERIALIZED VIEW ' || $1::text || E' AS\n' || pg_get_viewdef($1::text::regclass, true)`
+		args = []any{rel}
+	case KindIndex:
+		query = `SELECT pg_get_indexdef($1::text::regclass) || ';'`
+		args = []any{rel}
+	case KindSequence:
+		query = `SELECT format('CREATE SEQUENCE %s AS %s INCREMENT BY %s MINVALUE %s MAXVALUE %s START WITH %s CACHE %s%s;',
+		                      $1::text, data_type, increment_by, min_value, max_value, start_value, cache_size,
+		                      CASE WHEN cycle THEN ' CYCLE' ELSE '' END)
+		         FROM pg_sequences WHERE schemaname = $2 AND sequencename = $3`
+		args = []any{rel, o.Schema, o.Name}
+	case KindFunction, KindProcedure:
+		query = `SELECT pg_get_functiondef(p.oid)
+		         FROM pg_proc p
+		         JOIN pg_namespace n ON n.oid = p.pronamespace
+		         WHERE n.nspname = $1 AND p.proname || '(' || pg_get_function_identity_arguments(p.oid) || ')' = $2`
+		args = []any{o.Schema, o.Name}
+	case KindTrigger:
+		query = `SELECT pg_get_triggerdef(t.oid, true) || ';'
+		         FROM pg_trigger t
+		         JOIN pg_class c ON c.oid = t.tgrelid
+		         JOIN pg_namespace n ON n.oid = c.relnamespace
+		         WHERE n.nspname = $1 AND c.relname = $2 AND t.tgname = $3`
+		args = []any{o.Schema, o.Detail, o.Name}
+	default:
+		return "", fmt.Errorf("no DDL for %s", o.Kind)
+	}
+	var ddl string
+	if err := d.conn.QueryRow(ctx, query, args...).Scan(&ddl); err != nil {
+		return "", err
+	}
+	return ddl, nil
+}
+
+func (d *pgxDB) tableDDL(ctx context.Context, schema, table string) (string, error) {
+	cols, err := d.ListTableColumns(ctx, schema, table)
+	if err != nil {
+		return "", err
+	}
+	if len(cols) == 0 {
+		return "", fmt.Errorf("table %s.%s not found", schema, table)
+	}
+	rel := quotePostgresIdent(schema) + "." + quotePostgresIdent(table)
+
+	var defs []string
+	for _, c := range cols {
+		def := "    " + quotePostgresIdent(c.Name) + " " + c.DataType
+		if c.Default != "" {
+			def += " DEFAULT " + c.Default
+		}
+		if !c.Nullable {
+			def += " NOT NULL"
+		}
+		defs = append(defs, def)
+	}
+
+	rows, err := d.conn.Query(ctx, `SELECT conname, pg_get_constraintdef(oid) FROM pg_constraint
+	                                WHERE conrelid = $1::text::regclass
+	                                ORDER BY position(contype::text IN 'pucfx'), conname`, rel)
+	if err != nil {
+		return "", err
+	}
+	constraints, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (string, error) {
+		var name, def string
+		err := row.Scan(&name, &def)
+		return "    CONSTRAINT " + quotePostgresIdent(name) + " " + def, err
+	})
+	if err != nil {
+		return "", err
+	}
+	defs = append(defs, constraints...)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "CREATE TABLE %s (\n%s\n);\n", rel, strings.Join(defs, ",\n"))
+
+	rows, err = d.conn.Query(ctx, `SELECT pg_get_indexdef(i.indexrelid) FROM pg_index i
+	                               WHERE i.indrelid = $1::text::regclass
+	                                 AND NOT EXISTS (SELECT 1 FROM pg_constraint c WHERE c.conindid = i.indexrelid AND c.conrelid = i.indrelid)
+	                               ORDER BY 1`, rel)
+	if err != nil {
+		return "", err
+	}
+	indexes, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return "", err
+	}
+	if len(indexes) > 0 {
+		b.WriteString("\n")
+	}
+	for _, ix := range indexes {
+		b.WriteString(ix + ";\n")
+	}
+
+	var comment string
+	if err := d.conn.QueryRow(ctx, `SELECT COALESCE(obj_description($1::text::regclass, 'pg_class'), '')`, rel).Scan(&comment); err != nil {
+		return "", err
+	}
+	var comments []string
+	if comment != "" {
+		comments = append(comments, fmt.Sprintf("COMMENT ON TABLE %s IS %s;", rel, quoteLiteral(comment)))
+	}
+	for _, c := range cols {
+		if c.Comment != "" {
+			comments = append(comments, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", rel, quotePostgresIdent(c.Name), quoteLiteral(c.Comment)))
+		}
+	}
+	if len(comments) > 0 {
+		b.WriteString("\n" + strings.Join(comments, "\n") + "\n")
+	}
+	return b.String(), nil
 }
 
-func (d *pgxDB) FetchTableData(ctx context.Context, schema, table string, limit, offset int, sort *SortOption) (*QueryResult, error) {
-	query := fmt.Sprintf("SELECT * FROM %s.%s", quotePostgresIdent(schema), quotePostgresIdent(table))
+func pgWhere(filters []ColumnFilter) string {
+	if len(filters) == 0 {
+		return ""
+	}
+	conds := make([]string, len(filters))
+	for i, f := range filters {
+		conds[i] = quotePostgresIdent(f.Column) + " = " + quoteLiteral(f.Value)
+	}
+	return " WHERE " + strings.Join(conds, " AND ")
+}
+
+func (d *pgxDB) CountRows(ctx context.Context, schema, table string, filters []ColumnFilter) (int64, error) {
+	query := fmt.Sprintf("SELECT count(*) FROM %s.%s", quotePostgresIdent(schema), quotePostgresIdent(table)) + pgWhere(filters)
+	var n int64
+	err := d.conn.QueryRow(ctx, query).Scan(&n)
+	return n, err
+}
+
+func (d *pgxDB) FetchTableData(ctx context.Context, schema, table string, limit, offset int, sort *SortOption, filters []ColumnFilter) (*QueryResult, error) {
+	query := fmt.Sprintf("SELECT * FROM %s.%s", quotePostgresIdent(schema), quotePostgresIdent(table)) + pgWhere(filters)
 	if sort != nil && sort.Column != "" {
 		direction := "ASC"
 		if sort.Desc {
@@ -64,7 +465,57 @@ func (d *pgxDB) FetchTableData(ctx context.Context, schema, table string, limit,
 	if err != nil {
 		return nil, err
 	}
+	return d.collectRows(rows)
+}
+
+func (d *pgxDB) ProfileColumn(ctx context.Context, schema, table, column string, filters []ColumnFilter) (*ColumnProfile, error) {
+	return profileColumn(ctx, d, schema, table, column, func(ordered bool) profileSQL {
+		col := quotePostgresIdent(column)
+		q := profileSQL{
+			rel:    quotePostgresIdent(schema) + "." + quotePostgresIdent(table),
+			col:    col,
+			value:  col + "::text",
+			length: "length(" + col + "::text)",
+			number: col + "::float8",
+			query: func(ctx context.Context, sql string) (*QueryResult, error) {
+				rows, err := d.conn.Query(ctx, sql)
+				if err != nil {
+					return nil, err
+				}
+				return d.collectRows(rows)
+			},
+		}
+		if ordered {
+			q.value = col
+		}
+		if where := pgWhere(filters); where != "" {
+			q.where = []string{strings.TrimPrefix(where, " WHERE ")}
+		}
+		return q
+	})
+}
 
+func (d *pgxDB) searchTable(ctx context.Context, t SearchTarget, value string, limit int) (*QueryResult, error) {
+	names := make([]string, len(t.columns))
+	conds := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		names[i] = quotePostgresIdent(c.name)
+		if c.exact {
+			conds[i] = names[i] + " = " + value
+		} else {
+			conds[i] = names[i] + "::text ILIKE " + quoteLiteral(likePattern(value))
+		}
+	}
+	query := fmt.Sprintf("SELECT %s, %s FROM %s.%s WHERE %s LIMIT %d", strings.Join(names, ", "), strings.Join(conds, ", "),
+		quotePostgresIdent(t.Schema), quotePostgresIdent(t.Table), strings.Join(conds, " OR "), limit)
+	rows, err := d.conn.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return d.collectRows(rows)
+}
+
+func (d *pgxDB) collectRows(rows pgx.Rows) (*QueryResult, error) {
 	defer rows.Close()
 
 	fd := rows.FieldDescriptions()
@@ -73,24 +524,35 @@ func (d *pgxDB) FetchTableData(ctx context.Context, schema, table string, limit,
 		columns[i] = string(col.Name)
 	}
 
-	var resultRows [][]string
+	tm := d.conn.TypeMap()
+	result := &QueryResult{Columns: columns}
 	for rows.Next() {
 		values, err := rows.Values()
 		if err != nil {
 			return nil, err
 		}
+		raw := rows.RawValues()
 		row := make([]string, len(values))
+		nulls := make([]bool, len(values))
+		var rawRow []string
+		if d.format != (valueFormat{}) {
+			rawRow = make([]string, len(values))
+		}
 		for i, val := range values {
-			if val == nil {
-				row[i] = "NULL"
-			} else {
-				row[i] = fmt.Sprintf("%v", val)
+			row[i] = d.format.pgValue(tm, fd[i], raw[i], val)
+			nulls[i] = val == nil
+			if rawRow != nil {
+				rawRow[i] = valueFormat{}.pgValue(tm, fd[i], raw[i], val)
 			}
 		}
-		resultRows = append(resultRows, row)
+		result.Rows = append(result.Rows, row)
+		result.Nulls = append(result.Nulls, nulls)
+		if rawRow != nil {
+			result.Raw = append(result.Raw, rawRow)
+		}
 	}
 
-	return &QueryResult{Columns: columns, Rows: resultRows}, nil
+	return result, rows.Err()
 }
 
 func (d *pgxDB) ExecQuery(ctx context.Context, query string) (*QueryResult, error) {
@@ -98,32 +560,25 @@ func (d *pgxDB) ExecQuery(ctx context.Context, query string) (*QueryResult, erro
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	return d.collectRows(rows)
+}
 
-	fd := rows.FieldDescriptions()
-	columns := make([]string, len(fd))
-	for i, col := range fd {
-		columns[i] = string(col.Name)
-	}
+func (d *pgxDB) Ping(ctx context.Context) error {
+	return d.conn.Ping(ctx)
+}
 
-	var resultRows [][]string
-	for rows.Next() {
-		values, err := rows.Values()
-		if err != nil {
-			return nil, err
-		}
-		row := make([]string, len(values))
-		for i, val := range values {
-			if val == nil {
-				row[i] = "NULL"
-			} else {
-				row[i] = fmt.Sprintf("%v", val)
-			}
-		}
-		resultRows = append(resultRows, row)
-	}
+func (d *pgxDB) captureSession(ctx context.Context) (sessionState, error) {
+	var s sessionState
+	err := d.conn.QueryRow(ctx, "SELECT current_setting('search_path'), current_database()").Scan(&s.searchPath, &s.database)
+	return s, err
+}
 
-	return &QueryResult{Columns: columns, Rows: resultRows}, nil
+func (d *pgxDB) restoreSession(ctx context.Context, s sessionState) error {
+	if s.searchPath == "" {
+		return nil
+	}
+	_, err := d.conn.Exec(ctx, "SELECT set_config('search_path', $1, false)", s.searchPath)
+	return err
 }
 
 func (d *pgxDB) Close(ctx context.Context) error {
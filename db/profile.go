@@ -0,0 +1,181 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	profileTopN = 10
+	profileBins = 10
+)
+
+type ColumnProfile struct {
+	Column    string
+	DataType  string
+	Rows      int64
+	Nulls     int64
+	Distinct  int64
+	Min       string
+	Max       string
+	AvgLength float64
+	Top       []ValueCount
+	Histogram []HistogramBin
+	// "value" for numeric columns, "length" for text
+	HistogramOf string
+}
+
+type ValueCount struct {
+	Value string
+	Count int64
+}
+
+// HistogramBin counts the values in [Low, High).
+type HistogramBin struct {
+	Low   float64
+	High  float64
+	Count int64
+}
+
+var temporalTypes = map[string]bool{
+	"date": true, "datetime": true, "year": true, "timestamp": true, "timestamptz": true,
+	"timestamp with time zone": true, "timestamp without time zone": true,
+	"time": true, "timetz": true, "time with time zone": true, "time without time zone": true,
+}
+
+type profileSQL struct {
+	rel    string
+	col    string
+	value  string
+	length string
+	number string
+	where  []string
+	query  func(ctx context.Context, sql string) (*QueryResult, error)
+}
+
+func (q profileSQL) from(extra ...string) string {
+	conds := append(append([]string(nil), q.where...), extra...)
+	if len(conds) == 0 {
+		return " FROM " + q.rel
+	}
+	return " FROM " + q.rel + " WHERE " + strings.Join(conds, " AND ")
+}
+
+func (q profileSQL) summary() string {
+	return fmt.Sprintf("SELECT COUNT(*), COUNT(%[1]s), COUNT(DISTINCT %[2]s), MIN(%[2]s), MAX(%[2]s), AVG(%[3]s), MIN(%[3]s), MAX(%[3]s)",
+		q.col, q.value, q.length) + q.from()
+}
+
+func (q profileSQL) top() string {
+	return fmt.Sprintf("SELECT %s, COUNT(*)", q.value) + q.from(q.col+" IS NOT NULL") +
+		fmt.Sprintf(" GROUP BY 1 ORDER BY 2 DESC, 1 LIMIT %d", profileTopN)
+}
+
+// The maximum falls into the last bin.
+func (q profileSQL) bucket(expr string, lo, width float64, bins int) string {
+	b := fmt.Sprintf("LEAST(FLOOR((%s - %s) / %s) + 1, %d)", expr, sqlFloat(lo), sqlFloat(width), bins)
+	return "SELECT " + b + ", COUNT(*)" + q.from(q.col+" IS NOT NULL") + " GROUP BY 1 ORDER BY 1"
+}
+
+func sqlFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func profileInt(s string) int64 {
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
+
+func profileFloat(s string) (float64, bool) {
+	f, err := strconv.ParseFloat(s, 64)
+	return f, err == nil
+}
+
+func (q profileSQL) run(ctx context.Context, p *ColumnProfile, numeric bool) error {
+	res, err := q.query(ctx, q.summary())
+	if err != nil {
+		return err
+	}
+	if len(res.Rows) != 1 || len(res.Rows[0]) < 8 {
+		return fmt.Errorf("profile %s: unexpected summary", p.Column)
+	}
+	s := res.Rows[0]
+	p.Rows = profileInt(s[0])
+	p.Nulls = p.Rows - profileInt(s[1])
+	p.Distinct = profileInt(s[2])
+	p.Min, p.Max = s[3], s[4]
+	p.AvgLength, _ = profileFloat(s[5])
+	if p.Rows == p.Nulls {
+		return nil
+	}
+
+	if res, err = q.query(ctx, q.top()); err != nil {
+		return err
+	}
+	for _, row := range res.Rows {
+		p.Top = append(p.Top, ValueCount{Value: row[0], Count: profileInt(row[1])})
+	}
+
+	var expr string
+	var lo, hi, width float64
+	bins := profileBins
+	switch {
+	case numeric:
+		var ok1, ok2 bool
+		lo, ok1 = profileFloat(p.Min)
+		hi, ok2 = profileFloat(p.Max)
+		if !ok1 || !ok2 || math.IsInf(hi-lo, 0) || math.IsNaN(hi-lo) {
+			return nil
+		}
+		expr, p.HistogramOf = q.number, "value"
+		width = (hi - lo) / float64(bins)
+	case !temporalTypes[baseType(p.DataType)]:
+		lo, _ = profileFloat(s[6])
+		hi, _ = profileFloat(s[7])
+		expr, p.HistogramOf = q.length, "length"
+		bins = min(bins, int(hi-lo)+1)
+		width = (hi - lo + 1) / float64(bins)
+	default:
+		return nil
+	}
+	if width == 0 {
+		p.Histogram = []HistogramBin{{Low: lo, High: hi, Count: p.Rows - p.Nulls}}
+		return nil
+	}
+	if res, err = q.query(ctx, q.bucket(expr, lo, width, bins)); err != nil {
+		return err
+	}
+	p.Histogram = make([]HistogramBin, bins)
+	for i := range p.Histogram {
+		p.Histogram[i] = HistogramBin{Low: lo + float64(i)*width, High: lo + float64(i+1)*width}
+	}
+	for _, row := range res.Rows {
+		b, _ := profileFloat(row[0])
+		if k := int(b) - 1; k >= 0 && k < bins {
+			p.Histogram[k].Count = profileInt(row[1])
+		}
+	}
+	return nil
+}
+
+func profileColumn(ctx context.Context, d DB, schema, table, column string, build func(ordered bool) profileSQL) (*ColumnProfile, error) {
+	cols, err := d.ListTableColumns(ctx, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	p := &ColumnProfile{Column: column}
+	for _, c := range cols {
+		if c.Name == column {
+			p.DataType = c.DataType
+		}
+	}
+	numeric := numericSearchTypes[baseType(p.DataType)]
+	q := build(numeric || temporalTypes[baseType(p.DataType)])
+	if err := q.run(ctx, p, numeric); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
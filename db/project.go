@@ -0,0 +1,134 @@
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+var projectFiles = []string{".otto.toml", ".otto.json"}
+
+type Project struct {
+	Path        string
+	Connections []Config
+}
+
+func (p *Project) Dir() string {
+	return filepath.Dir(p.Path)
+}
+
+type projectConnection struct {
+	Config
+	PasswordEnv string `json:"password_env"`
+}
+
+func FindProjectFile(dir string) string {
+	for _, d := range []string{dir, gitRoot(dir)} {
+		if d == "" {
+			continue
+		}
+		for _, name := range projectFiles {
+			p := filepath.Join(d, name)
+			if _, err := os.Stat(p); err == nil {
+				return p
+			}
+		}
+	}
+	return ""
+}
+
+func gitRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func LoadProject() (*Project, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	path := FindProjectFile(wd)
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []map[string]any
+	if strings.HasSuffix(path, ".toml") {
+		entries, err = parseProjectTOML(data)
+	} else {
+		entries, err = parseProjectJSON(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+
+	project := &Project{Path: path}
+	for i, entry := range entries {
+		for k, v := range entry {
+			switch v := v.(type) {
+			case float64:
+				entry[k] = strconv.FormatFloat(v, 'f', -1, 64)
+			case int64:
+				entry[k] = strconv.FormatInt(v, 10)
+			}
+		}
+		raw, err := json.Marshal(entry)
+		if err != nil {
+			return nil, err
+		}
+		var pc projectConnection
+		if err := json.Unmarshal(raw, &pc); err != nil {
+			return nil, fmt.Errorf("%s: connection %d: %w", filepath.Base(path), i+1, err)
+		}
+		cfg := pc.Config
+		cfg.Password = ""
+		if pc.PasswordEnv != "" {
+			cfg.Password = os.Getenv(pc.PasswordEnv)
+		}
+		if cfg.Driver == "" {
+			cfg.Driver = DriverPostgres
+		}
+		project.Connections = append(project.Connections, cfg)
+	}
+	return project, nil
+}
+
+func parseProjectJSON(data []byte) ([]map[string]any, error) {
+	trimmed := bytes.TrimSpace(data)
+	var entries []map[string]any
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		err := json.Unmarshal(trimmed, &entries)
+		return entries, err
+	}
+	var doc struct {
+		Connections []map[string]any `json:"connections"`
+	}
+	err := json.Unmarshal(trimmed, &doc)
+	return doc.Connections, err
+}
+
+func parseProjectTOML(data []byte) ([]map[string]any, error) {
+	var doc struct {
+		Connections []map[string]any `toml:"connections"`
+	}
+	_, err := toml.Decode(string(data), &doc)
+	return doc.Connections, err
+}
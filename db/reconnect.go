@@ -0,0 +1,374 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var reconnectBackoff = []time.Duration{
+	0,
+	500 * time.Millisecond,
+	time.Second,
+	2 * time.Second,
+	4 * time.Second,
+}
+
+var errClosed = errors.New("connection closed")
+
+var errTxAborted = errors.New("transaction aborted by reconnect: the connection was lost and its uncommitted work was rolled back")
+
+type sessionState struct {
+	searchPath string
+	database   string
+}
+
+type sessionKeeper interface {
+	captureSession(ctx context.Context) (sessionState, error)
+	restoreSession(ctx context.Context, s sessionState) error
+}
+
+// ReconnectEvent reports that a lost connection is being re-established,
+// or, with Reconnecting false, how that ended.
+type ReconnectEvent struct {
+	Reconnecting bool
+	Err          error
+}
+
+type reconnectingDB struct {
+	mu         sync.Mutex
+	cfg        Config
+	inner      DB
+	session    sessionState
+	hasSession bool
+	closed     bool
+	inTx       bool
+	txAborted  bool
+	events     chan ReconnectEvent
+}
+
+func newReconnectingDB(ctx context.Context, cfg Config) (*reconnectingDB, error) {
+	inner, err := dial(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	d := &reconnectingDB{cfg: cfg, inner: inner, events: make(chan ReconnectEvent, 1)}
+	d.captureSession(ctx)
+	return d, nil
+}
+
+func dial(ctx context.Context, cfg Config) (DB, error) {
+	format, err := newValueFormat(cfg)
+	if err != nil {
+		return nil, err
+	}
+	switch cfg.Driver {
+	case DriverMySQL:
+		return newMysqlDB(ctx, cfg.DSN(), cfg.ReadOnly, format)
+	default:
+		return newPgxDB(ctx, cfg.DSN(), cfg.ReadOnly, format)
+	}
+}
+
+func (d *reconnectingDB) captureSession(ctx context.Context) {
+	keeper, ok := d.inner.(sessionKeeper)
+	if !ok {
+		return
+	}
+	s, err := keeper.captureSession(ctx)
+	if err != nil {
+		return
+	}
+	d.session = s
+	d.hasSession = true
+}
+
+func (d *reconnectingDB) connectionLost(ctx context.Context) bool {
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	return d.inner.Ping(pingCtx) != nil
+}
+
+// ReconnectEvents returns the channel d reports reconnects on, or nil when
+// d does not reconnect by itself. Only the latest event is kept.
+func ReconnectEvents(d DB) <-chan ReconnectEvent {
+	if r, ok := d.(*reconnectingDB); ok {
+		return r.events
+	}
+	return nil
+}
+
+func (d *reconnectingDB) notify(ev ReconnectEvent) {
+	select {
+	case <-d.events:
+	default:
+	}
+	d.events <- ev
+}
+
+func (d *reconnectingDB) reconnect(ctx context.Context) error {
+	d.notify(ReconnectEvent{Reconnecting: true})
+	err := d.redial(ctx)
+	d.notify(ReconnectEvent{Err: err})
+	return err
+}
+
+func (d *reconnectingDB) redial(ctx context.Context) error {
+	var lastErr error
+	for _, delay := range reconnectBackoff {
+		if delay > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+		inner, err := dial(ctx, d.cfg)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if keeper, ok := inner.(sessionKeeper); ok && d.hasSession {
+			if err := keeper.restoreSession(ctx, d.session); err != nil {
+				inner.Close(ctx)
+				lastErr = fmt.Errorf("restore session: %w", err)
+				continue
+			}
+		}
+		d.inner.Close(ctx)
+		d.inner = inner
+		if d.inTx {
+			d.inTx, d.txAborted = false, true
+		}
+		return nil
+	}
+	return lastErr
+}
+
+func withRetry[T any](ctx context.Context, d *reconnectingDB, idempotent bool, fn func(DB) (T, error)) (T, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var zero T
+	if d.closed {
+		return zero, errClosed
+	}
+	if !idempotent && d.txAborted {
+		d.txAborted = false
+		return zero, errTxAborted
+	}
+	v, err := fn(d.inner)
+	if err == nil || ctx.Err() != nil || !d.connectionLost(ctx) {
+		return v, err
+	}
+	if rerr := d.reconnect(ctx); rerr != nil {
+		return v, fmt.Errorf("%w (reconnect failed: %v)", err, rerr)
+	}
+	if !idempotent {
+		if d.txAborted {
+			d.txAborted = false
+			return zero, errTxAborted
+		}
+		return zero, fmt.Errorf("connection was lost and restored, statement was not re-run: %w", err)
+	}
+	return fn(d.inner)
+}
+
+func (d *reconnectingDB) ListDatabases(ctx context.Context) ([]string, error) {
+	return withRetry(ctx, d, true, func(inner DB) ([]string, error) {
+		return inner.ListDatabases(ctx)
+	})
+}
+
+func (d *reconnectingDB) SwitchDatabase(ctx context.Context, name string) error {
+	_, err := withRetry(ctx, d, true, func(inner DB) (struct{}, error) {
+		return struct{}{}, inner.SwitchDatabase(ctx, name)
+	})
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.cfg.DBName = name
+	d.hasSession = false
+	d.captureSession(ctx)
+	return nil
+}
+
+func (d *reconnectingDB) ListSchemas(ctx context.Context) ([]string, error) {
+	return withRetry(ctx, d, true, func(inner DB) ([]string, error) {
+		return inner.ListSchemas(ctx)
+	})
+}
+
+func (d *reconnectingDB) ListBaseTables(ctx context.Context, schema string) ([]Object, error) {
+	return withRetry(ctx, d, true, func(inner DB) ([]Object, error) {
+		return inner.ListBaseTables(ctx, schema)
+	})
+}
+
+func (d *reconnectingDB) ListViews(ctx context.Context, schema string) ([]Object, error) {
+	return withRetry(ctx, d, true, func(inner DB) ([]Object, error) {
+		return inner.ListViews(ctx, schema)
+	})
+}
+
+func (d *reconnectingDB) ListMaterializedViews(ctx context.Context, schema string) ([]Object, error) {
+	return withRetry(ctx, d, true, func(inner DB) ([]Object, error) {
+		return inner.ListMaterializedViews(ctx, schema)
+	})
+}
+
+func (d *reconnectingDB) ListSchemaIndexes(ctx context.Context, schema string) ([]Object, error) {
+	return withRetry(ctx, d, true, func(inner DB) ([]Object, error) {
+		return inner.ListSchemaIndexes(ctx, schema)
+	})
+}
+
+func (d *reconnectingDB) ObjectDDL(ctx context.Context, o Object) (string, error) {
+	return withRetry(ctx, d, true, func(inner DB) (string, error) {
+		return inner.ObjectDDL(ctx, o)
+	})
+}
+
+func (d *reconnectingDB) ListSequences(ctx context.Context, schema string) ([]Object, error) {
+	return withRetry(ctx, d, true, func(inner DB) ([]Object, error) {
+		return inner.ListSequences(ctx, schema)
+	})
+}
+
+func (d *reconnectingDB) ListFunctions(ctx context.Context, schema string) ([]Object, error) {
+	return withRetry(ctx, d, true, func(inner DB) ([]Object, error) {
+		return inner.ListFunctions(ctx, schema)
+	})
+}
+
+func (d *reconnectingDB) ListTriggers(ctx context.Context, schema string) ([]Object, error) {
+	return withRetry(ctx, d, true, func(inner DB) ([]Object, error) {
+		return inner.ListTriggers(ctx, schema)
+	})
+}
+
+func (d *reconnectingDB) ListTables(ctx context.Context) ([]Table, error) {
+	return withRetry(ctx, d, true, func(inner DB) ([]Table, error) {
+		return inner.ListTables(ctx)
+	})
+}
+
+func (d *reconnectingDB) ListColumns(ctx context.Context) ([]Column, error) {
+	return withRetry(ctx, d, true, func(inner DB) ([]Column, error) {
+		return inner.ListColumns(ctx)
+	})
+}
+
+func (d *reconnectingDB) ListSchemaColumns(ctx context.Context, schema string) ([]Column, error) {
+	return withRetry(ctx, d, true, func(inner DB) ([]Column, error) {
+		return inner.ListSchemaColumns(ctx, schema)
+	})
+}
+
+func (d *reconnectingDB) ListTableColumns(ctx context.Context, schema, table string) ([]Column, error) {
+	return withRetry(ctx, d, true, func(inner DB) ([]Column, error) {
+		return inner.ListTableColumns(ctx, schema, table)
+	})
+}
+
+func (d *reconnectingDB) ListIndexes(ctx context.Context, schema, table string) ([]Index, error) {
+	return withRetry(ctx, d, true, func(inner DB) ([]Index, error) {
+		return inner.ListIndexes(ctx, schema, table)
+	})
+}
+
+func (d *reconnectingDB) ListConstraints(ctx context.Context, schema, table string) ([]Constraint, error) {
+	return withRetry(ctx, d, true, func(inner DB) ([]Constraint, error) {
+		return inner.ListConstraints(ctx, schema, table)
+	})
+}
+
+func (d *reconnectingDB) ListForeignKeys(ctx context.Context, schema, table string) ([]ForeignKey, error) {
+	return withRetry(ctx, d, true, func(inner DB) ([]ForeignKey, error) {
+		return inner.ListForeignKeys(ctx, schema, table)
+	})
+}
+
+func (d *reconnectingDB) ListReferencingKeys(ctx context.Context, schema, table string) ([]ForeignKey, error) {
+	return withRetry(ctx, d, true, func(inner DB) ([]ForeignKey, error) {
+		return inner.ListReferencingKeys(ctx, schema, table)
+	})
+}
+
+func (d *reconnectingDB) ListSchemaForeignKeys(ctx context.Context, schema string) ([]ForeignKey, error) {
+	return withRetry(ctx, d, true, func(inner DB) ([]ForeignKey, error) {
+		return inner.ListSchemaForeignKeys(ctx, schema)
+	})
+}
+
+func (d *reconnectingDB) ListTableTriggers(ctx context.Context, schema, table string) ([]Trigger, error) {
+	return withRetry(ctx, d, true, func(inner DB) ([]Trigger, error) {
+		return inner.ListTableTriggers(ctx, schema, table)
+	})
+}
+
+func (d *reconnectingDB) FetchTableData(ctx context.Context, schema, table string, limit, offset int, sort *SortOption, filters []ColumnFilter) (*QueryResult, error) {
+	return withRetry(ctx, d, true, func(inner DB) (*QueryResult, error) {
+		return inner.FetchTableData(ctx, schema, table, limit, offset, sort, filters)
+	})
+}
+
+func (d *reconnectingDB) CountRows(ctx context.Context, schema, table string, filters []ColumnFilter) (int64, error) {
+	return withRetry(ctx, d, true, func(inner DB) (int64, error) {
+		return inner.CountRows(ctx, schema, table, filters)
+	})
+}
+
+func (d *reconnectingDB) ProfileColumn(ctx context.Context, schema, table, column string, filters []ColumnFilter) (*ColumnProfile, error) {
+	return withRetry(ctx, d, true, func(inner DB) (*ColumnProfile, error) {
+		return inner.ProfileColumn(ctx, schema, table, column, filters)
+	})
+}
+
+func (d *reconnectingDB) ExecQuery(ctx context.Context, query string) (*QueryResult, error) {
+	res, err := withRetry(ctx, d, false, func(inner DB) (*QueryResult, error) {
+		return inner.ExecQuery(ctx, query)
+	})
+	if err == nil {
+		d.mu.Lock()
+		d.inTx = transactionOpen(query, d.cfg.Driver, d.inTx)
+		if changesSession(query, d.cfg.Driver) {
+			d.captureSession(ctx)
+		}
+		d.mu.Unlock()
+	}
+	return res, err
+}
+
+func (d *reconnectingDB) Ping(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return errClosed
+	}
+	err := d.inner.Ping(ctx)
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return err
+	}
+	return d.reconnect(ctx)
+}
+
+func (d *reconnectingDB) Close(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+	close(d.events)
+	return d.inner.Close(ctx)
+}
@@ -0,0 +1,503 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokWord tokenKind = iota
+	tokQuotedIdent
+	tokString
+	tokPunct
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	depth int
+}
+
+type Statement struct {
+	Text   string
+	tokens []token
+}
+
+// SplitStatements tokenizes query the way driver reads it: backslashes
+// escape inside MySQL strings and Postgres E'…' literals only.
+func SplitStatements(query string, driver Driver) []Statement {
+	var stmts []Statement
+	var cur []token
+	depth := 0
+	start := 0
+	runes := []rune(query)
+
+	flush := func(end int) {
+		text := strings.TrimSpace(string(runes[start:end]))
+		if len(cur) > 0 {
+			stmts = append(stmts, Statement{Text: text, tokens: cur})
+		}
+		cur = nil
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-', r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/') {
+				i++
+			}
+			i += 2
+		case r == '\'':
+			escapes := driver == DriverMySQL || i > 0 && (runes[i-1] == 'e' || runes[i-1] == 'E') && (i < 2 || !isWordRune(runes[i-2]))
+			end := scanQuoted(runes, i, '\'', escapes)
+			cur = append(cur, token{kind: tokString, text: string(runes[i:end]), depth: depth})
+			i = end
+		case r == '"' || r == '`':
+			end := scanQuoted(runes, i, r, driver == DriverMySQL && r == '"')
+			text := string(runes[i+1 : max(i+1, end-1)])
+			cur = append(cur, token{kind: tokQuotedIdent, text: text, depth: depth})
+			i = end
+		case r == '$' && dollarTag(runes, i) != "":
+			tag := []rune(dollarTag(runes, i))
+			end := indexRunes(runes, tag, i+len(tag))
+			if end < 0 {
+				end = len(runes)
+			} else {
+				end += len(tag)
+			}
+			cur = append(cur, token{kind: tokString, text: string(runes[i:end]), depth: depth})
+			i = end
+		case r == ';':
+			flush(i)
+			i++
+			start = i
+			depth = 0
+		case r == '(':
+			cur = append(cur, token{kind: tokPunct, text: "(", depth: depth})
+			depth++
+			i++
+		case r == ')':
+			if depth > 0 {
+				depth--
+			}
+			cur = append(cur, token{kind: tokPunct, text: ")", depth: depth})
+			i++
+		case isWordRune(r):
+			j := i
+			for j < len(runes) && isWordRune(runes[j]) {
+				j++
+			}
+			cur = append(cur, token{kind: tokWord, text: strings.ToLower(string(runes[i:j])), depth: depth})
+			i = j
+		default:
+			cur = append(cur, token{kind: tokPunct, text: string(r), depth: depth})
+			i++
+		}
+	}
+	flush(len(runes))
+	return stmts
+}
+
+func scanQuoted(runes []rune, i int, quote rune, escapes bool) int {
+	j := i + 1
+	for j < len(runes) {
+		if runes[j] == '\\' && escapes {
+			j += 2
+			continue
+		}
+		if runes[j] == quote {
+			if j+1 < len(runes) && runes[j+1] == quote {
+				j += 2
+				continue
+			}
+			return j + 1
+		}
+		j++
+	}
+	return len(runes)
+}
+
+func dollarTag(runes []rune, i int) string {
+	j := i + 1
+	for j < len(runes) && (unicode.IsLetter(runes[j]) || runes[j] == '_' || (j > i+1 && unicode.IsDigit(runes[j]))) {
+		j++
+	}
+	if j < len(runes) && runes[j] == '$' {
+		return string(runes[i : j+1])
+	}
+	return ""
+}
+
+func indexRunes(runes, sub []rune, from int) int {
+	for i := from; i+len(sub) <= len(runes); i++ {
+		match := true
+		for j := range sub {
+			if runes[i+j] != sub[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '$'
+}
+
+func (s Statement) Keyword() string {
+	for _, t := range s.tokens {
+		if t.kind == tokWord {
+			return t.text
+		}
+		if t.kind != tokPunct || t.text != "(" {
+			return ""
+		}
+	}
+	return ""
+}
+
+func (s Statement) hasWord(word string) bool {
+	for _, t := range s.tokens {
+		if t.kind == tokWord && t.text == word {
+			return true
+		}
+	}
+	return false
+}
+
+var readOnlyKeywords = map[string]bool{
+	"select": true, "show": true, "describe": true, "desc": true,
+	"explain": true, "with": true, "values": true, "table": true,
+	"set": true, "use": true, "begin": true, "start": true,
+	"commit": true, "rollback": true, "savepoint": true, "release": true,
+	"help": true,
+}
+
+var dataModifyingWords = []string{"insert", "update", "delete", "merge", "replace", "truncate", "drop", "alter", "create"}
+
+func (s Statement) IsWrite() bool {
+	kw := s.Keyword()
+	if kw == "" {
+		return false
+	}
+	if !readOnlyKeywords[kw] {
+		return true
+	}
+	switch kw {
+	case "with":
+		for _, w := range dataModifyingWords {
+			if s.hasWord(w) {
+				return true
+			}
+		}
+	case "explain":
+		if s.hasWord("analyze") {
+			for _, w := range dataModifyingWords {
+				if s.hasWord(w) {
+					return true
+				}
+			}
+		}
+	case "select":
+		if s.hasWord("setval") || s.hasWord("nextval") {
+			return true
+		}
+		for i, t := range s.tokens {
+			if t.kind == tokWord && t.text == "into" && t.depth == 0 {
+				next := i + 1
+				return next >= len(s.tokens) || s.tokens[next].text != "@"
+			}
+		}
+	}
+	return false
+}
+
+var sessionKeywords = map[string]bool{"set": true, "use": true, "reset": true, "discard": true}
+
+func changesSession(query string, driver Driver) bool {
+	for _, s := range SplitStatements(query, driver) {
+		if sessionKeywords[s.Keyword()] || s.hasWord("search_path") || s.hasWord("set_config") {
+			return true
+		}
+	}
+	return false
+}
+
+func transactionOpen(query string, driver Driver, open bool) bool {
+	for _, s := range SplitStatements(query, driver) {
+		switch s.Keyword() {
+		case "begin", "start":
+			open = true
+		case "commit", "end", "abort":
+			open = false
+		case "rollback":
+			if !s.hasWord("to") {
+				open = false
+			}
+		}
+	}
+	return open
+}
+
+func IsWriteStatement(query string, driver Driver) bool {
+	for _, s := range SplitStatements(query, driver) {
+		if s.IsWrite() {
+			return true
+		}
+	}
+	return false
+}
+
+var accessModeSettings = map[string]bool{
+	"transaction_read_only":         true,
+	"default_transaction_read_only": true,
+	"tx_read_only":                  true,
+}
+
+func stringValue(t token) string {
+	text := t.text
+	if strings.HasPrefix(text, "$") {
+		if tag := dollarTag([]rune(text), 0); tag != "" {
+			text = strings.TrimSuffix(strings.TrimPrefix(text, tag), tag)
+		}
+		return text
+	}
+	text = strings.TrimSuffix(strings.TrimPrefix(text, "'"), "'")
+	return strings.ReplaceAll(text, "''", "'")
+}
+
+func (s Statement) changesAccessMode() bool {
+	setConfig := s.hasWord("set_config")
+	for i, t := range s.tokens {
+		if t.kind == tokString {
+			name := strings.ToLower(strings.TrimSpace(stringValue(t)))
+			if accessModeSettings[name] || setConfig && strings.HasSuffix(name, "_read_only") {
+				return true
+			}
+			continue
+		}
+		if t.kind != tokWord {
+			continue
+		}
+		if accessModeSettings[t.text] {
+			return true
+		}
+		if t.text == "read" && i+1 < len(s.tokens) && s.tokens[i+1].text == "write" {
+			return true
+		}
+	}
+	return false
+}
+
+func CheckReadOnly(query string, driver Driver) error {
+	for _, s := range SplitStatements(query, driver) {
+		if s.IsWrite() {
+			return fmt.Errorf("read-only connection: %s statements are not allowed", strings.ToUpper(s.Keyword()))
+		}
+		if s.changesAccessMode() {
+			return fmt.Errorf("read-only connection: changing the transaction access mode is not allowed")
+		}
+	}
+	return nil
+}
+
+type Hazard string
+
+const (
+	HazardDeleteWithoutWhere Hazard = "delete-without-where"
+	HazardUpdateWithoutWhere Hazard = "update-without-where"
+	HazardTruncate           Hazard = "truncate"
+	HazardDrop               Hazard = "drop"
+	HazardAlterDrop          Hazard = "alter-drop"
+)
+
+var AllHazards = []Hazard{
+	HazardDeleteWithoutWhere,
+	HazardUpdateWithoutWhere,
+	HazardTruncate,
+	HazardDrop,
+	HazardAlterDrop,
+}
+
+func (h Hazard) Describe() string {
+	switch h {
+	case HazardDeleteWithoutWhere:
+		return "DELETE without WHERE"
+	case HazardUpdateWithoutWhere:
+		return "UPDATE without WHERE"
+	case HazardTruncate:
+		return "TRUNCATE"
+	case HazardDrop:
+		return "DROP"
+	case HazardAlterDrop:
+		return "ALTER … DROP"
+	}
+	return string(h)
+}
+
+type Destructive struct {
+	Hazard    Hazard
+	Target    string
+	Object    string
+	Statement string
+}
+
+var dropObjectWords = map[string]bool{
+	"table": true, "view": true, "materialized": true, "index": true,
+	"schema": true, "database": true, "sequence": true, "function": true,
+	"procedure": true, "trigger": true, "type": true, "extension": true,
+	"temporary": true, "temp": true, "concurrently": true, "if": true,
+	"exists": true, "only": true, "low_priority": true, "ignore": true,
+	"quick": true, "event": true, "user": true, "role": true,
+}
+
+func (s Statement) skipWords(i int, skip map[string]bool) int {
+	for i < len(s.tokens) && s.tokens[i].kind == tokWord && skip[s.tokens[i].text] {
+		i++
+	}
+	return i
+}
+
+func (s Statement) qualifiedName(i int) (string, string) {
+	var parts []string
+	for i < len(s.tokens) {
+		t := s.tokens[i]
+		if t.kind != tokWord && t.kind != tokQuotedIdent {
+			break
+		}
+		parts = append(parts, t.text)
+		if i+1 < len(s.tokens) && s.tokens[i+1].text == "." {
+			i += 2
+			continue
+		}
+		break
+	}
+	if len(parts) == 0 {
+		return "", ""
+	}
+	return strings.Join(parts, "."), parts[len(parts)-1]
+}
+
+func (s Statement) indexOf(word string, from int) int {
+	for i := from; i < len(s.tokens); i++ {
+		if s.tokens[i].kind == tokWord && s.tokens[i].text == word && s.tokens[i].depth == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+var cteHazardVerbs = map[string]bool{"delete": true, "update": true, "truncate": true}
+
+var verbModifiers = map[string]bool{"for": true, "key": true, "on": true, "do": true}
+
+func (s Statement) indexWithin(word string, from int) int {
+	depth := s.tokens[from].depth
+	for i := from; i < len(s.tokens) && s.tokens[i].depth >= depth; i++ {
+		if s.tokens[i].kind == tokWord && s.tokens[i].text == word && s.tokens[i].depth == depth {
+			return i
+		}
+	}
+	return -1
+}
+
+var columnPropertyDrops = map[string]bool{
+	"default": true, "not": true, "expression": true, "identity": true,
+}
+
+func (s Statement) dropsSomething(from int) bool {
+	for i := s.indexOf("drop", from); i >= 0; i = s.indexOf("drop", i+1) {
+		if next := i + 1; next >= len(s.tokens) || !columnPropertyDrops[s.tokens[next].text] {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Statement) Destructive() []Destructive {
+	start := -1
+	for i, t := range s.tokens {
+		if t.kind == tokWord {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil
+	}
+	if s.tokens[start].text != "with" {
+		if d, ok := s.destructiveAt(start); ok {
+			return []Destructive{d}
+		}
+		return nil
+	}
+	var found []Destructive
+	for i := start + 1; i < len(s.tokens); i++ {
+		t := s.tokens[i]
+		if t.kind != tokWord || !cteHazardVerbs[t.text] || verbModifiers[s.tokens[i-1].text] {
+			continue
+		}
+		if d, ok := s.destructiveAt(i); ok {
+			found = append(found, d)
+		}
+	}
+	return found
+}
+
+func (s Statement) destructiveAt(start int) (Destructive, bool) {
+	d := Destructive{Statement: s.Text}
+	switch s.tokens[start].text {
+	case "delete":
+		if s.indexWithin("where", start) >= 0 {
+			return d, false
+		}
+		d.Hazard = HazardDeleteWithoutWhere
+		if from := s.indexWithin("from", start); from >= 0 {
+			d.Target, d.Object = s.qualifiedName(s.skipWords(from+1, dropObjectWords))
+		}
+	case "update":
+		if s.indexWithin("where", start) >= 0 {
+			return d, false
+		}
+		d.Hazard = HazardUpdateWithoutWhere
+		d.Target, d.Object = s.qualifiedName(s.skipWords(start+1, dropObjectWords))
+	case "truncate":
+		d.Hazard = HazardTruncate
+		d.Target, d.Object = s.qualifiedName(s.skipWords(start+1, dropObjectWords))
+	case "drop":
+		d.Hazard = HazardDrop
+		d.Target, d.Object = s.qualifiedName(s.skipWords(start+1, dropObjectWords))
+	case "alter":
+		if !s.dropsSomething(start) {
+			return d, false
+		}
+		d.Hazard = HazardAlterDrop
+		d.Target, d.Object = s.qualifiedName(s.skipWords(start+1, dropObjectWords))
+	default:
+		return d, false
+	}
+	return d, true
+}
+
+func FindDestructive(query string, driver Driver) []Destructive {
+	var found []Destructive
+	for _, s := range SplitStatements(query, driver) {
+		found = append(found, s.Destructive()...)
+	}
+	return found
+}
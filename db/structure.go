@@ -0,0 +1,53 @@
+package db
+
+import "strings"
+
+type Index struct {
+	Name       string
+	Columns    []string
+	Unique     bool
+	Primary    bool
+	Definition string
+	Size       int64
+}
+
+type ConstraintKind string
+
+const (
+	ConstraintPrimaryKey ConstraintKind = "PRIMARY KEY"
+	ConstraintUnique     ConstraintKind = "UNIQUE"
+	ConstraintCheck      ConstraintKind = "CHECK"
+)
+
+type Constraint struct {
+	Name       string
+	Kind       ConstraintKind
+	Columns    []string
+	Definition string
+}
+
+type ForeignKey struct {
+	Name       string
+	Schema     string
+	Table      string
+	Columns    []string
+	RefSchema  string
+	RefTable   string
+	RefColumns []string
+	OnUpdate   string
+	OnDelete   string
+}
+
+type Trigger struct {
+	Name       string
+	Timing     string
+	Events     string
+	Definition string
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
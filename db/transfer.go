@@ -0,0 +1,236 @@
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgservicefile"
+)
+
+type ImportFormat string
+
+const (
+	FormatAuto      ImportFormat = "auto"
+	FormatOtto      ImportFormat = "otto"
+	FormatDBeaver   ImportFormat = "dbeaver"
+	FormatPgAdmin   ImportFormat = "pgadmin"
+	FormatPgService ImportFormat = "pgservice"
+)
+
+func ExportConnections(w io.Writer, cfgs []Config, includePasswords bool) error {
+	out := make([]Config, len(cfgs))
+	for i, cfg := range cfgs {
+		if !includePasswords {
+			cfg.Password = ""
+		}
+		cfg.ID = ""
+		out[i] = cfg
+	}
+	data, err := json.MarshalIndent(historyDoc{Version: historyVersion, Connections: out}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+func ParseConnections(data []byte, format ImportFormat) ([]Config, ImportFormat, error) {
+	if format == "" || format == FormatAuto {
+		format = detectFormat(data)
+	}
+	var (
+		cfgs []Config
+		err  error
+	)
+	switch format {
+	case FormatOtto:
+		cfgs, err = parseOttoConnections(data)
+	case FormatDBeaver:
+		cfgs, err = parseDBeaverConnections(data)
+	case FormatPgAdmin:
+		cfgs, err = parsePgAdminConnections(data)
+	case FormatPgService:
+		cfgs, err = parsePgServiceConnections(data)
+	default:
+		return nil, format, fmt.Errorf("unknown import format %q", format)
+	}
+	return cfgs, format, err
+}
+
+func detectFormat(data []byte) ImportFormat {
+	trimmed := bytes.TrimSpace(data)
+	if !json.Valid(trimmed) {
+		return FormatPgService
+	}
+	if trimmed[0] == '[' {
+		return FormatOtto
+	}
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &probe); err != nil {
+		return FormatOtto
+	}
+	switch {
+	case probe["Servers"] != nil:
+		return FormatPgAdmin
+	case probe["connections"] != nil && probe["version"] == nil:
+		return FormatDBeaver
+	}
+	return FormatOtto
+}
+
+func parseOttoConnections(data []byte) ([]Config, error) {
+	trimmed := bytes.TrimSpace(data)
+	var cfgs []Config
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &cfgs); err != nil {
+			return nil, fmt.Errorf("parse otto connections: %w", err)
+		}
+		return cfgs, nil
+	}
+	var doc historyDoc
+	if err := json.Unmarshal(trimmed, &doc); err != nil {
+		return nil, fmt.Errorf("parse otto connections: %w", err)
+	}
+	return doc.Connections, nil
+}
+
+type dbeaverDataSources struct {
+	Connections map[string]struct {
+		Provider      string `json:"provider"`
+		Driver        string `json:"driver"`
+		Name          string `json:"name"`
+		Folder        string `json:"folder"`
+		Configuration struct {
+			Host     string `json:"host"`
+			Port     string `json:"port"`
+			Database string `json:"database"`
+			User     string `json:"user"`
+			Password string `json:"password"`
+			Type     string `json:"type"`
+		} `json:"configuration"`
+	} `json:"connections"`
+}
+
+func parseDBeaverConnections(data []byte) ([]Config, error) {
+	var src dbeaverDataSources
+	if err := json.Unmarshal(data, &src); err != nil {
+		return nil, fmt.Errorf("parse DBeaver data sources: %w", err)
+	}
+	var cfgs []Config
+	for _, c := range src.Connections {
+		var driver Driver
+		switch strings.ToLower(c.Provider) {
+		case "postgresql", "postgres":
+			driver = DriverPostgres
+		case "mysql", "mariadb":
+			driver = DriverMySQL
+		default:
+			continue
+		}
+		cfg := Config{
+			Name:     c.Name,
+			Group:    c.Folder,
+			Driver:   driver,
+			Host:     c.Configuration.Host,
+			Port:     c.Configuration.Port,
+			User:     c.Configuration.User,
+			Password: c.Configuration.Password,
+			DBName:   c.Configuration.Database,
+		}
+		switch c.Configuration.Type {
+		case "prod":
+			cfg.Env = "prod"
+		case "test":
+			cfg.Env = "staging"
+		case "dev":
+			cfg.Env = "dev"
+		}
+		cfgs = append(cfgs, cfg)
+	}
+	if cfgs == nil && len(src.Connections) > 0 {
+		return nil, errors.New("no PostgreSQL or MySQL connections found")
+	}
+	sortByGroupAndName(cfgs)
+	return cfgs, nil
+}
+
+func sortByGroupAndName(cfgs []Config) {
+	sort.SliceStable(cfgs, func(i, j int) bool {
+		if cfgs[i].Group != cfgs[j].Group {
+			return cfgs[i].Group < cfgs[j].Group
+		}
+		return cfgs[i].Name < cfgs[j].Name
+	})
+}
+
+type pgAdminServers struct {
+	Servers map[string]struct {
+		Name          string          `json:"Name"`
+		Group         string          `json:"Group"`
+		Host          string          `json:"Host"`
+		Port          json.RawMessage `json:"Port"`
+		MaintenanceDB string          `json:"MaintenanceDB"`
+		Username      string          `json:"Username"`
+	} `json:"Servers"`
+}
+
+func parsePgAdminConnections(data []byte) ([]Config, error) {
+	var src pgAdminServers
+	if err := json.Unmarshal(data, &src); err != nil {
+		return nil, fmt.Errorf("parse pgAdmin servers: %w", err)
+	}
+	var cfgs []Config
+	for _, s := range src.Servers {
+		cfg := Config{
+			Name:   s.Name,
+			Group:  s.Group,
+			Driver: DriverPostgres,
+			User:   s.Username,
+			DBName: s.MaintenanceDB,
+		}
+		if strings.HasPrefix(s.Host, "/") {
+			cfg.Socket = s.Host
+		} else {
+			cfg.Host = s.Host
+		}
+		port := strings.Trim(string(s.Port), `"`)
+		if _, err := strconv.Atoi(port); err == nil && port != "5432" {
+			cfg.Port = port
+		}
+		cfgs = append(cfgs, cfg)
+	}
+	sortByGroupAndName(cfgs)
+	return cfgs, nil
+}
+
+func parsePgServiceConnections(data []byte) ([]Config, error) {
+	sf, err := pgservicefile.ParseServicefile(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("parse pg_service.conf: %w", err)
+	}
+	var cfgs []Config
+	for _, svc := range sf.Services {
+		set := svc.Settings
+		cfg := Config{
+			Name:     svc.Name,
+			Driver:   DriverPostgres,
+			Port:     set["port"],
+			User:     set["user"],
+			Password: set["password"],
+			DBName:   set["dbname"],
+		}
+		if strings.HasPrefix(set["host"], "/") {
+			cfg.Socket = set["host"]
+		} else {
+			cfg.Host = set["host"]
+		}
+		cfgs = append(cfgs, cfg)
+	}
+	return cfgs, nil
+}
@@ -0,0 +1,189 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+type ValueHit struct {
+	Schema string
+	Table  string
+	Column string
+	Value  string
+	Rows   int
+}
+
+type ValueSearchEvent struct {
+	Schema string
+	Table  string
+	Hits   []ValueHit
+	Err    error
+}
+
+type ValueSearchOptions struct {
+	Workers  int
+	RowLimit int
+	Timeout  time.Duration
+}
+
+var DefaultValueSearch = ValueSearchOptions{Workers: 4, RowLimit: 50, Timeout: 30 * time.Second}
+
+type searchColumn struct {
+	name  string
+	exact bool
+}
+
+type SearchTarget struct {
+	Schema  string
+	Table   string
+	columns []searchColumn
+}
+
+type valueSearcher interface {
+	searchTable(ctx context.Context, t SearchTarget, value string, limit int) (*QueryResult, error)
+}
+
+var errValueSearchUnsupported = errors.New("value search is not supported by this driver")
+
+var numericValueRe = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+var (
+	textSearchTypes = map[string]bool{
+		"text": true, "tinytext": true, "mediumtext": true, "longtext": true, "citext": true, "name": true, "uuid": true,
+		"character varying": true, "varchar": true, "character": true, "char": true, "bpchar": true, "enum": true, "set": true,
+	}
+	numericSearchTypes = map[string]bool{
+		"smallint": true, "integer": true, "bigint": true, "int": true, "tinyint": true, "mediumint": true,
+		"numeric": true, "decimal": true, "real": true, "double precision": true, "double": true, "float": true,
+	}
+)
+
+func baseType(dataType string) string {
+	t := strings.ToLower(dataType)
+	if strings.HasSuffix(t, "[]") {
+		return t
+	}
+	if i := strings.IndexByte(t, '('); i >= 0 {
+		t = t[:i]
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(t, " zerofill"), " unsigned"))
+}
+
+func searchColumnKind(dataType string) (text, numeric bool) {
+	t := baseType(dataType)
+	return textSearchTypes[t], numericSearchTypes[t]
+}
+
+func PlanValueSearch(columns []Column, value string) []SearchTarget {
+	numeric := numericValueRe.MatchString(value)
+	var targets []SearchTarget
+	index := map[[2]string]int{}
+	for _, c := range columns {
+		text, num := searchColumnKind(c.DataType)
+		if !text && !(num && numeric) {
+			continue
+		}
+		key := [2]string{c.Schema, c.Table}
+		k, ok := index[key]
+		if !ok {
+			k = len(targets)
+			index[key] = k
+			targets = append(targets, SearchTarget{Schema: c.Schema, Table: c.Table})
+		}
+		targets[k].columns = append(targets[k].columns, searchColumn{name: c.Name, exact: !text})
+	}
+	return targets
+}
+
+func likePattern(value string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + r.Replace(value) + "%"
+}
+
+// hits reads the matches off the flags searchTable selects after the
+// columns, so the server's collation decides what matches.
+func (t SearchTarget) hits(result *QueryResult) []ValueHit {
+	var out []ValueHit
+	index := map[[2]string]int{}
+	for r, row := range result.Rows {
+		for i, c := range t.columns {
+			matched := len(t.columns) + i
+			if matched >= len(row) || result.IsNull(r, i) || (row[matched] != "1" && row[matched] != "true") {
+				continue
+			}
+			cell := row[i]
+			key := [2]string{c.name, cell}
+			if k, ok := index[key]; ok {
+				out[k].Rows++
+				continue
+			}
+			index[key] = len(out)
+			out = append(out, ValueHit{Schema: t.Schema, Table: t.Table, Column: c.name, Value: cell, Rows: 1})
+		}
+	}
+	return out
+}
+
+// SearchValue closes events when every target is done or ctx is cancelled.
+func SearchValue(ctx context.Context, cfg Config, targets []SearchTarget, value string, opts ValueSearchOptions, events chan<- ValueSearchEvent) {
+	defer close(events)
+	jobs := make(chan SearchTarget)
+	send := func(ev ValueSearchEvent) bool {
+		select {
+		case events <- ev:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	var wg sync.WaitGroup
+	for range min(max(opts.Workers, 1), len(targets)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn, err := dial(ctx, cfg)
+			if err == nil {
+				defer conn.Close(context.Background())
+			}
+			for t := range jobs {
+				ev := ValueSearchEvent{Schema: t.Schema, Table: t.Table, Err: err}
+				if err == nil {
+					ev.Hits, ev.Err = searchTarget(ctx, conn, t, value, opts)
+				}
+				if ctx.Err() != nil || !send(ev) {
+					return
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, t := range targets {
+		select {
+		case jobs <- t:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+func searchTarget(ctx context.Context, conn DB, t SearchTarget, value string, opts ValueSearchOptions) ([]ValueHit, error) {
+	s, ok := conn.(valueSearcher)
+	if !ok {
+		return nil, errValueSearchUnsupported
+	}
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+	result, err := s.searchTable(ctx, t, value, opts.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	return t.hits(result), nil
+}
@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"otto/db"
 )
@@ -41,12 +43,23 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, tea.Quit
 		}
 	case ConnectedMsg:
-		if a.connect.editingIndex >= 0 {
-			db.UpdateConnection(a.connect.editingIndex, msg.Cfg)
-		} else {
-			db.SaveConnection(msg.Cfg)
+		msg.Cfg.LastUsed = time.Now()
+		var saveErr error
+		switch {
+		case a.connect.fromProject:
+		case a.connect.editingID != "":
+			msg.Cfg.ID = a.connect.editingID
+			saveErr = db.UpdateConnection(msg.Cfg.ID, msg.Cfg)
+		default:
+			var id string
+			if id, saveErr = db.SaveConnection(msg.Cfg); saveErr == nil {
+				msg.Cfg.ID = id
+			}
+		}
+		a.main = NewMainModel(msg.DB, msg.Cfg, a.width, a.height, a.main.sessions)
+		if saveErr != nil {
+			a.main.notice = "could not save connection: " + saveErr.Error()
 		}
-		a.main = NewMainModel(msg.DB, msg.Cfg, a.width, a.height)
 		a.state = stateMain
 		return a, a.main.Init()
 	case GoBackToConnectMsg:
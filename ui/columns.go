@@ -0,0 +1,322 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"otto/db"
+)
+
+const (
+	autoColWidth = 30
+	minColWidth  = 3
+	maxColWidth  = 200
+)
+
+// Column indexes refer to the result's own columns; order lists them as shown.
+type columnLayout struct {
+	names  []string
+	order  []int
+	hidden []bool
+	widths []int
+	pinned int
+}
+
+func newColumnLayout(names []string, saved db.ColumnLayout) columnLayout {
+	l := columnLayout{
+		names:  names,
+		hidden: make([]bool, len(names)),
+		widths: make([]int, len(names)),
+	}
+	for _, name := range saved.Order {
+		if i := slices.Index(names, name); i >= 0 && !slices.Contains(l.order, i) {
+			l.order = append(l.order, i)
+		}
+	}
+	for i := range names {
+		if !slices.Contains(l.order, i) {
+			l.order = append(l.order, i)
+		}
+	}
+	for _, name := range saved.Hidden {
+		if i := slices.Index(names, name); i >= 0 {
+			l.hidden[i] = true
+		}
+	}
+	if len(l.visible()) == 0 {
+		clear(l.hidden)
+	}
+	for name, w := range saved.Widths {
+		if i := slices.Index(names, name); i >= 0 {
+			l.widths[i] = min(max(w, minColWidth), maxColWidth)
+		}
+	}
+	l.pinned = min(max(saved.Pinned, 0), len(l.visible()))
+	return l
+}
+
+func (l columnLayout) sameColumns(names []string) bool {
+	return l.names != nil && slices.Equal(l.names, names)
+}
+
+func (l columnLayout) saved() db.ColumnLayout {
+	var s db.ColumnLayout
+	if !slices.IsSorted(l.order) {
+		for _, i := range l.order {
+			s.Order = append(s.Order, l.names[i])
+		}
+	}
+	for i, h := range l.hidden {
+		if h {
+			s.Hidden = append(s.Hidden, l.names[i])
+		}
+	}
+	for i, w := range l.widths {
+		if w > 0 {
+			if s.Widths == nil {
+				s.Widths = map[string]int{}
+			}
+			s.Widths[l.names[i]] = w
+		}
+	}
+	s.Pinned = l.pinned
+	return s
+}
+
+func (l columnLayout) visible() []int {
+	var out []int
+	for _, i := range l.order {
+		if !l.hidden[i] {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+func (l columnLayout) hiddenCount() int {
+	n := 0
+	for _, h := range l.hidden {
+		if h {
+			n++
+		}
+	}
+	return n
+}
+
+func (l columnLayout) step(col, delta int) int {
+	vis := l.visible()
+	if len(vis) == 0 {
+		return col
+	}
+	pos := slices.Index(vis, col)
+	if pos < 0 {
+		return vis[0]
+	}
+	return vis[min(max(pos+delta, 0), len(vis)-1)]
+}
+
+func (l *columnLayout) move(col, delta int) {
+	vis := l.visible()
+	pos := slices.Index(vis, col)
+	target := pos + delta
+	if pos < 0 || target < 0 || target >= len(vis) {
+		return
+	}
+	if (pos < l.pinned) != (target < l.pinned) {
+		return
+	}
+	a, b := slices.Index(l.order, col), slices.Index(l.order, vis[target])
+	l.order[a], l.order[b] = l.order[b], l.order[a]
+}
+
+func (l *columnLayout) hide(col int) int {
+	vis := l.visible()
+	pos := slices.Index(vis, col)
+	if pos < 0 || len(vis) == 1 {
+		return col
+	}
+	l.hidden[col] = true
+	if pos < l.pinned {
+		l.pinned--
+	}
+	if pos+1 < len(vis) {
+		return vis[pos+1]
+	}
+	return vis[pos-1]
+}
+
+func (l *columnLayout) showAll() {
+	clear(l.hidden)
+}
+
+func (l *columnLayout) togglePin(col int) {
+	pos := slices.Index(l.visible(), col)
+	if pos < 0 {
+		return
+	}
+	if l.pinned == pos+1 {
+		l.pinned = 0
+	} else {
+		l.pinned = pos + 1
+	}
+}
+
+func (l *columnLayout) resize(col, delta, current int) {
+	l.widths[col] = min(max(current+delta, minColWidth), maxColWidth)
+}
+
+func (l *columnLayout) autoWidth(col int) {
+	l.widths[col] = 0
+}
+
+func (l columnLayout) describe() string {
+	var parts []string
+	if n := l.hiddenCount(); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d hidden", n))
+	}
+	if l.pinned > 0 {
+		parts = append(parts, fmt.Sprintf("%d pinned", l.pinned))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "  · " + strings.Join(parts, ", ")
+}
+
+func contentWidths(result *db.QueryResult) []int {
+	widths := make([]int, len(result.Columns))
+	for i, col := range result.Columns {
+		widths[i] = min(len([]rune(col)), autoColWidth)
+	}
+	for _, row := range result.Rows {
+		for i, val := range row {
+			if idx := strings.IndexAny(val, "\n\r"); idx >= 0 {
+				val = val[:idx]
+			}
+			widths[i] = min(max(widths[i], len([]rune(val))), autoColWidth)
+		}
+	}
+	return widths
+}
+
+func (l columnLayout) displayWidth(col, content int, label string) int {
+	if w := l.widths[col]; w > 0 {
+		return w
+	}
+	return max(content, len([]rune(label)))
+}
+
+type gridBorders struct {
+	left, mid, pin, right string
+}
+
+var (
+	gridCellBorders = gridBorders{"│ ", " │ ", " ┃ ", " │"}
+	gridSepBorders  = gridBorders{"├─", "─┼─", "─╂─", "─┤"}
+)
+
+func (l columnLayout) gridLine(cells []string, b gridBorders, first, w int) string {
+	pinned := min(l.pinned, len(cells))
+	rest := cells[pinned+min(first, max(len(cells)-pinned-1, 0)):]
+	switch {
+	case pinned == 0:
+		return clipLine(b.left+strings.Join(rest, b.mid)+b.right, w)
+	case len(rest) == 0:
+		return clipLine(b.left+strings.Join(cells, b.mid)+b.right, w)
+	}
+	return clipLine(b.left+strings.Join(cells[:pinned], b.mid)+b.pin+strings.Join(rest, b.mid)+b.right, w)
+}
+
+func (l columnLayout) cellOffsets(cells []string, first, start int) []int {
+	pinned := min(l.pinned, len(cells))
+	first = min(first, max(len(cells)-pinned-1, 0))
+	offsets := make([]int, len(cells))
+	pos := start + 2
+	for k, cell := range cells {
+		if k >= pinned && k < pinned+first {
+			offsets[k] = -1
+			continue
+		}
+		offsets[k] = pos
+		pos += len([]rune(cell)) + 3
+	}
+	return offsets
+}
+
+func (l columnLayout) scrollRoom(widths []int, w int) int {
+	room := w - 2
+	for _, cw := range widths[:min(l.pinned, len(widths))] {
+		room -= cw + 3
+	}
+	return room
+}
+
+func (l columnLayout) follow(first, pos int, widths []int, w int) int {
+	pinned := min(l.pinned, len(widths))
+	first = min(max(first, 0), max(len(widths)-pinned-1, 0))
+	if pos < pinned {
+		return first
+	}
+	rel := pos - pinned
+	if rel < first {
+		return rel
+	}
+	room := l.scrollRoom(widths, w)
+	for first < rel && spanWidth(widths[pinned+first:pos+1]) > room {
+		first++
+	}
+	return first
+}
+
+func spanWidth(widths []int) int {
+	n := -1
+	for _, w := range widths {
+		n += w + 3
+	}
+	return n
+}
+
+func (l columnLayout) shownRange(first int, widths []int, w int) string {
+	if len(widths) == 0 {
+		return ""
+	}
+	pinned := min(l.pinned, len(widths))
+	room := l.scrollRoom(widths, w)
+	last := pinned + first
+	for last+1 < len(widths) && spanWidth(widths[pinned+first:last+2]) <= room {
+		last++
+	}
+	if first == 0 && last == len(widths)-1 && spanWidth(widths[pinned:]) <= room {
+		return ""
+	}
+	return fmt.Sprintf("  · columns %d–%d of %d", pinned+first+1, last+1, len(widths))
+}
+
+func (l *columnLayout) handleKey(key string, col, content int) (int, bool) {
+	switch key {
+	case "<":
+		l.move(col, -1)
+	case ">":
+		l.move(col, 1)
+	case "x":
+		col = l.hide(col)
+	case "X":
+		l.showAll()
+	case "P":
+		l.togglePin(col)
+	case "+", "-":
+		delta := 2
+		if key == "-" {
+			delta = -2
+		}
+		l.resize(col, delta, l.displayWidth(col, content, l.names[col]))
+	case "=":
+		l.autoWidth(col)
+	case "0":
+		*l = newColumnLayout(l.names, db.ColumnLayout{})
+	default:
+		return col, false
+	}
+	return col, true
+}
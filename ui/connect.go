@@ -17,9 +17,14 @@ const (
 	fieldDriver
 	fieldHost
 	fieldPort
+	fieldSocket
 	fieldUser
 	fieldPassword
 	fieldDBName
+	fieldGroup
+	fieldEnv
+	fieldEnvColor
+	fieldAccess
 	fieldCount
 )
 
@@ -126,6 +131,11 @@ var (
 			Foreground(lipgloss.Color("#79C0FF")).
 			Padding(0, 1)
 
+	histTagROStyle = lipgloss.NewStyle().
+			Background(lipgloss.Color("#30363D")).
+			Foreground(lipgloss.Color("#79C0FF")).
+			Padding(0, 1)
+
 	histTagMYStyle = lipgloss.NewStyle().
 			Background(lipgloss.Color("#3B2300")).
 			Foreground(lipgloss.Color("#F7A663")).
@@ -137,6 +147,19 @@ var (
 	histHelpStyle = lipgloss.NewStyle().Foreground(mutedColor)
 )
 
+func renderEnvTag(cfg db.Config) string {
+	env := cfg.Environment()
+	if env.Name == "" {
+		return ""
+	}
+	return lipgloss.NewStyle().
+		Background(lipgloss.Color(env.Color)).
+		Foreground(darkColor).
+		Bold(true).
+		Padding(0, 1).
+		Render(strings.ToUpper(env.Name))
+}
+
 type ConnectedMsg struct {
 	DB  db.DB
 	Cfg db.Config
@@ -146,6 +169,10 @@ type connectErrMsg struct {
 	err error
 }
 
+type diagnosisMsg struct {
+	diagnosis db.Diagnosis
+}
+
 type ConnectModel struct {
 	inputs          []textinput.Model
 	focused         int
@@ -156,8 +183,19 @@ type ConnectModel struct {
 	history         []db.Config
 	selectedHistory int
 	historyFocused  bool
+	collapsed       map[string]bool
+	historySort     historySort
+	historySearch   bool
+	historyQuery    string
 	driver          db.Driver
-	editingIndex    int
+	readOnly        bool
+	editingID       string
+	testing         bool
+	diagnosis       *db.Diagnosis
+	historyErr      error
+	project         *db.Project
+	projectErr      error
+	fromProject     bool
 }
 
 func NewConnectModel() ConnectModel {
@@ -180,6 +218,9 @@ func NewConnectModel() ConnectModel {
 			t.Placeholder = "localhost"
 		case fieldPort:
 			t.Placeholder = "5432"
+		case fieldSocket:
+			t.Placeholder = "/var/run/postgresql  (optional)"
+			t.CharLimit = 256
 		case fieldUser:
 			t.Placeholder = "postgres"
 		case fieldPassword:
@@ -187,21 +228,34 @@ func NewConnectModel() ConnectModel {
 			t.EchoMode = textinput.EchoPassword
 		case fieldDBName:
 			t.Placeholder = "mydb"
+		case fieldGroup:
+			t.Placeholder = "Work / Client A  (optional)"
+		case fieldEnv:
+			t.Placeholder = "dev · staging · prod  (optional)"
+			t.CharLimit = 16
+		case fieldEnvColor:
+			t.Placeholder = "#FF4444  (optional)"
+			t.CharLimit = 7
 		}
 		inputs[i] = t
 	}
 
-	history := db.LoadHistory()
+	history, historyErr := db.LoadHistory()
+	project, projectErr := db.LoadProject()
 
-	return ConnectModel{
+	m := ConnectModel{
 		inputs:          inputs,
 		focused:         fieldName,
 		history:         history,
+		historyErr:      historyErr,
+		project:         project,
+		projectErr:      projectErr,
 		selectedHistory: -1,
-		historyFocused:  len(history) > 0,
+		collapsed:       map[string]bool{},
 		driver:          db.DriverPostgres,
-		editingIndex:    -1,
 	}
+	m.historyFocused = m.showHistory()
+	return m
 }
 
 func (m ConnectModel) Init() tea.Cmd { return textinput.Blink }
@@ -209,9 +263,11 @@ func (m ConnectModel) Init() tea.Cmd { return textinput.Blink }
 func (m *ConnectModel) applyDriverDefaults() {
 	if m.driver == db.DriverMySQL {
 		m.inputs[fieldPort].Placeholder = "3306"
+		m.inputs[fieldSocket].Placeholder = "/var/run/mysqld/mysqld.sock  (optional)"
 		m.inputs[fieldUser].Placeholder = "root"
 	} else {
 		m.inputs[fieldPort].Placeholder = "5432"
+		m.inputs[fieldSocket].Placeholder = "/var/run/postgresql  (optional)"
 		m.inputs[fieldUser].Placeholder = "postgres"
 	}
 }
@@ -227,22 +283,55 @@ func (m *ConnectModel) toggleDriver() {
 	m.applyDriverDefaults()
 }
 
-func (m *ConnectModel) connectToHistory(idx int) tea.Cmd {
-	if idx < 0 || idx >= len(m.history) {
-		return nil
-	}
-	cfg := m.history[idx]
-	m.driver = cfg.Driver
-	if m.driver == "" {
-		m.driver = db.DriverPostgres
-	}
+func (m *ConnectModel) fillInputs(cfg db.Config) {
 	m.inputs[fieldName].SetValue(cfg.Name)
 	m.inputs[fieldDriver].SetValue(string(m.driver))
 	m.inputs[fieldHost].SetValue(cfg.Host)
 	m.inputs[fieldPort].SetValue(cfg.Port)
+	m.inputs[fieldSocket].SetValue(cfg.Socket)
 	m.inputs[fieldUser].SetValue(cfg.User)
 	m.inputs[fieldPassword].SetValue(cfg.Password)
 	m.inputs[fieldDBName].SetValue(cfg.DBName)
+	m.inputs[fieldGroup].SetValue(cfg.Group)
+	m.inputs[fieldEnv].SetValue(cfg.Env)
+	m.inputs[fieldEnvColor].SetValue(cfg.EnvColor)
+	m.readOnly = cfg.ReadOnly
+}
+
+func (m ConnectModel) formConfig() db.Config {
+	return db.Config{
+		Name:     m.inputs[fieldName].Value(),
+		Driver:   m.driver,
+		Host:     m.inputs[fieldHost].Value(),
+		Port:     m.inputs[fieldPort].Value(),
+		Socket:   m.inputs[fieldSocket].Value(),
+		User:     m.inputs[fieldUser].Value(),
+		Password: m.inputs[fieldPassword].Value(),
+		DBName:   m.inputs[fieldDBName].Value(),
+		Group:    strings.TrimSpace(m.inputs[fieldGroup].Value()),
+		Env:      strings.TrimSpace(m.inputs[fieldEnv].Value()),
+		EnvColor: strings.TrimSpace(m.inputs[fieldEnvColor].Value()),
+		ReadOnly: m.readOnly,
+	}
+}
+
+func (m ConnectModel) withSaved(cfg db.Config) db.Config {
+	for _, h := range m.history {
+		if m.editingID != "" && h.ID == m.editingID || m.editingID == "" && db.DisplayName(h) == db.DisplayName(cfg) {
+			return cfg.WithSaved(h)
+		}
+	}
+	return cfg
+}
+
+func (m *ConnectModel) connectToRow(row historyRow) tea.Cmd {
+	cfg := m.rowConfig(row)
+	m.fromProject = row.project
+	m.driver = cfg.Driver
+	if m.driver == "" {
+		m.driver = db.DriverPostgres
+	}
+	m.fillInputs(cfg)
 	m.historyFocused = false
 	m.connecting = true
 	m.err = nil
@@ -264,74 +353,7 @@ func (m ConnectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.KeyMsg:
 		if m.historyFocused {
-			switch msg.Type {
-			case tea.KeyDown:
-				if m.selectedHistory < len(m.history)-1 {
-					m.selectedHistory++
-				}
-				return m, nil
-			case tea.KeyUp:
-				if m.selectedHistory > 0 {
-					m.selectedHistory--
-				}
-				return m, nil
-			case tea.KeyEnter:
-				if m.selectedHistory >= 0 {
-					return m, m.connectToHistory(m.selectedHistory)
-				}
-				return m, nil
-			case tea.KeyTab, tea.KeyEsc:
-				m.historyFocused = false
-				return m, nil
-			}
-			if msg.Type == tea.KeyRunes && len(msg.Runes) == 1 && msg.Runes[0] == 'd' {
-				if m.selectedHistory >= 0 && m.selectedHistory < len(m.history) {
-					db.DeleteConnection(m.history[m.selectedHistory])
-					m.history = db.LoadHistory()
-					if len(m.history) == 0 {
-						m.historyFocused = false
-						m.selectedHistory = -1
-					} else if m.selectedHistory >= len(m.history) {
-						m.selectedHistory = len(m.history) - 1
-					}
-				}
-				return m, nil
-			}
-			if msg.Type == tea.KeyRunes && len(msg.Runes) == 1 {
-				r := msg.Runes[0]
-				if r >= '1' && r <= '9' {
-					idx := int(r - '1')
-					if idx < len(m.history) {
-						return m, m.connectToHistory(idx)
-					}
-				}
-			}
-			if msg.Type == tea.KeyRunes && len(msg.Runes) == 1 && msg.Runes[0] == 'e' {
-				if m.selectedHistory >= 0 && m.selectedHistory < len(m.history) {
-					cfg := m.history[m.selectedHistory]
-					m.editingIndex = m.selectedHistory
-					m.driver = cfg.Driver
-					if m.driver == "" {
-						m.driver = db.DriverPostgres
-					}
-					m.applyDriverDefaults()
-					m.inputs[fieldName].SetValue(cfg.Name)
-					m.inputs[fieldDriver].SetValue(string(m.driver))
-					m.inputs[fieldHost].SetValue(cfg.Host)
-					m.inputs[fieldPort].SetValue(cfg.Port)
-					m.inputs[fieldUser].SetValue(cfg.User)
-					m.inputs[fieldPassword].SetValue(cfg.Password)
-					m.inputs[fieldDBName].SetValue(cfg.DBName)
-					m.historyFocused = false
-					m.focused = fieldName
-					for i := range m.inputs {
-						m.inputs[i].Blur()
-					}
-					m.inputs[fieldName].Focus()
-				}
-				return m, nil
-			}
-			return m, nil
+			return m.updateHistory(msg)
 		}
 
 		switch msg.Type {
@@ -350,35 +372,44 @@ func (m ConnectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.toggleDriver()
 				return m, nil
 			}
+			if m.focused == fieldAccess {
+				m.readOnly = !m.readOnly
+				return m, nil
+			}
 			if m.focused == fieldName {
 				m.inputs[m.focused].Blur()
 				m.focused = fieldDriver
 				m.inputs[m.focused].Focus()
 				return m, nil
 			}
-			if len(m.history) > 0 {
+			if m.showHistory() {
 				m.historyFocused = true
-				m.editingIndex = -1
+				m.editingID = ""
 				if m.selectedHistory < 0 {
 					m.selectedHistory = 0
 				}
 			}
 			return m, nil
+		case tea.KeyCtrlT:
+			if m.connecting || m.testing {
+				return m, nil
+			}
+			m.testing = true
+			m.err = nil
+			m.diagnosis = nil
+			cfg := m.formConfig()
+			return m, func() tea.Msg {
+				return diagnosisMsg{diagnosis: db.Diagnose(context.Background(), cfg)}
+			}
 		case tea.KeyEnter:
-			if m.connecting {
+			if m.connecting || m.testing {
 				return m, nil
 			}
 			m.connecting = true
 			m.err = nil
-			cfg := db.Config{
-				Name:     m.inputs[fieldName].Value(),
-				Driver:   m.driver,
-				Host:     m.inputs[fieldHost].Value(),
-				Port:     m.inputs[fieldPort].Value(),
-				User:     m.inputs[fieldUser].Value(),
-				Password: m.inputs[fieldPassword].Value(),
-				DBName:   m.inputs[fieldDBName].Value(),
-			}
+			m.diagnosis = nil
+			m.fromProject = false
+			cfg := m.withSaved(m.formConfig())
 			return m, func() tea.Msg {
 				conn, err := db.Connect(context.Background(), cfg)
 				if err != nil {
@@ -392,9 +423,14 @@ func (m ConnectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.err = msg.err
 		m.connecting = false
 		return m, nil
+
+	case diagnosisMsg:
+		m.testing = false
+		m.diagnosis = &msg.diagnosis
+		return m, nil
 	}
 
-	if m.focused != fieldDriver {
+	if m.focused != fieldDriver && m.focused != fieldAccess {
 		var cmd tea.Cmd
 		m.inputs[m.focused], cmd = m.inputs[m.focused].Update(msg)
 		return m, cmd
@@ -412,15 +448,16 @@ func (m ConnectModel) View() string {
 	if h == 0 {
 		h = 24
 	}
-	
-	if len(m.history) > 0 && w >= sideBySideMinW {
+
+	showHistory := m.showHistory()
+	if showHistory && w >= sideBySideMinW {
 		form := m.renderForm()
 		hist := m.renderHistory()
 		panels := lipgloss.JoinHorizontal(lipgloss.Top, form, "  ", hist)
 		return lipgloss.Place(w, h, lipgloss.Center, lipgloss.Center, panels)
 	}
-	
-	if m.historyFocused && len(m.history) > 0 {
+
+	if m.historyFocused && showHistory {
 		return lipgloss.Place(w, h, lipgloss.Center, lipgloss.Center, m.renderHistory())
 	}
 	return lipgloss.Place(w, h, lipgloss.Center, lipgloss.Center, m.renderForm())
@@ -435,7 +472,7 @@ func (m ConnectModel) renderForm() string {
 	sep := lipgloss.NewStyle().Foreground(dimColor).Render(strings.Repeat("─", panelW-4))
 
 	titleText := icon + "  otto"
-	if m.editingIndex >= 0 {
+	if m.editingID != "" {
 		titleText = icon + "  otto  ✎ Edit"
 	}
 	leftH := cHeaderTitle.Render(titleText)
@@ -446,7 +483,7 @@ func (m ConnectModel) renderForm() string {
 	}
 	header := leftH + strings.Repeat(" ", gap) + rightH
 
-	labels := []string{"Name", "Driver", "Host", "Port", "User", "Password", "Database"}
+	labels := []string{"Name", "Driver", "Host", "Port", "Socket", "User", "Password", "Database", "Group", "Env", "Color", "Access"}
 	var rows []string
 
 	for i, inp := range m.inputs {
@@ -471,6 +508,15 @@ func (m ConnectModel) renderForm() string {
 				fieldGap.Render("  ·  ") +
 				myS.Render("mysql") +
 				"  " + hint
+		} else if i == fieldAccess {
+			rwS, roS := driverOnStyle, driverOffStyle
+			if m.readOnly {
+				rwS, roS = driverOffStyle, driverOnStyle
+			}
+			val = rwS.Render("read-write") +
+				fieldGap.Render("  ·  ") +
+				roS.Render("read-only") +
+				"  " + driverHintStyle.Render("Tab")
 		} else {
 			val = inp.View()
 		}
@@ -483,7 +529,7 @@ func (m ConnectModel) renderForm() string {
 		}
 
 		rows = append(rows, row)
-		if i == fieldName {
+		if i == fieldName || i == fieldDBName {
 			rows = append(rows, sep)
 		}
 	}
@@ -499,9 +545,11 @@ func (m ConnectModel) renderForm() string {
 		status = errStyle.Render("✕  " + msg)
 	case m.connecting:
 		status = btnConnecting.Render("⟳  Connecting…")
+	case m.testing:
+		status = btnConnecting.Render("⟳  Testing connection…")
 	default:
 		btnText := "  Connect  "
-		if m.editingIndex >= 0 {
+		if m.editingID != "" {
 			btnText = " Save & Connect "
 		}
 		btn := btnConnect.Render(btnText)
@@ -513,7 +561,7 @@ func (m ConnectModel) renderForm() string {
 		status = strings.Repeat(" ", pad) + btn
 	}
 
-	hint := cHelpStyle.Render("↑↓ navigate · Enter connect · Ctrl+C quit")
+	hint := cHelpStyle.Render("↑↓ move · Enter connect · Ctrl+T test · Ctrl+C quit")
 
 	inner := lipgloss.JoinVertical(lipgloss.Left,
 		header,
@@ -521,7 +569,7 @@ func (m ConnectModel) renderForm() string {
 		fields,
 		"",
 		status,
-		"",
+		m.renderDiagnosis(),
 		hint,
 	)
 
@@ -536,71 +584,63 @@ func (m ConnectModel) renderForm() string {
 	return pStyle.Width(panelW).Render(inner)
 }
 
-func (m ConnectModel) renderHistory() string {
-	title := histTitleStyle.Render("Recent Connections")
-	sep := lipgloss.NewStyle().Foreground(dimColor).Render(strings.Repeat("─", histPanelW-4))
+var (
+	diagOKStyle   = lipgloss.NewStyle().Foreground(greenColor)
+	diagWarnStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#E3B341"))
+	diagFailStyle = errStyle
+	diagNameStyle = lipgloss.NewStyle().Foreground(textColor).Width(15)
+	diagHintStyle = lipgloss.NewStyle().Foreground(mutedColor).Italic(true)
+)
 
-	max := len(m.history)
-	if max > 9 {
-		max = 9
+func (m ConnectModel) renderDiagnosis() string {
+	if m.diagnosis == nil {
+		return ""
 	}
-
-	var rows []string
-	for i := 0; i < max; i++ {
-		cfg := m.history[i]
-		active := i == m.selectedHistory
-
-		var num string
-		if active {
-			num = histNumStyle.Render(fmt.Sprintf("%d", i+1))
-		} else {
-			num = histNumMutedStyle.Render(fmt.Sprintf("%d", i+1))
+	maxW := panelW - 4
+	clip := func(s string, w int) string {
+		if r := []rune(s); len(r) > w {
+			return string(r[:w-1]) + "…"
 		}
+		return s
+	}
 
-		var tag string
-		if cfg.Driver == db.DriverMySQL {
-			tag = histTagMYStyle.Render("my")
-		} else {
-			tag = histTagPGStyle.Render("pg")
+	lines := []string{""}
+	for _, step := range m.diagnosis.Steps {
+		var icon string
+		switch step.Status {
+		case db.StepOK:
+			icon = diagOKStyle.Render("✓")
+		case db.StepWarn:
+			icon = diagWarnStyle.Render("!")
+		case db.StepFail:
+			icon = diagFailStyle.Render("✕")
+		default:
+			icon = diagHintStyle.Render("·")
 		}
-
-		name := db.DisplayName(cfg)
-		if len(name) > histPanelW-14 {
-			name = name[:histPanelW-17] + "…"
+		detail := step.Detail
+		if step.Status == db.StepSkipped {
+			detail = "skipped"
 		}
-		var nameStr string
-		if active {
-			nameStr = histNameActiveStyle.Render(name)
-		} else {
-			nameStr = histNameStyle.Render(name)
+		if step.Elapsed > 0 {
+			detail += fmt.Sprintf(" (%dms)", step.Elapsed.Milliseconds())
 		}
-
-		if active {
-			rows = append(rows, fieldArrow.Render("▸")+" "+num+"  "+tag+"  "+nameStr)
-		} else {
-			rows = append(rows, "  "+num+"  "+tag+"  "+nameStr)
+		lines = append(lines, icon+" "+diagNameStyle.Render(step.Name)+histNameStyle.Render(clip(detail, maxW-17)))
+		if step.Hint != "" {
+			hint := lipgloss.NewStyle().Width(maxW - 4).Render(step.Hint)
+			for _, l := range strings.Split(hint, "\n") {
+				lines = append(lines, "    "+diagHintStyle.Render(strings.TrimRight(l, " ")))
+			}
 		}
 	}
-
-	list := strings.Join(rows, "\n")
-	hint := histHelpStyle.Render("↑↓ select · 1-9/Enter · d delete · Esc · e Edit")
-
-	inner := lipgloss.JoinVertical(lipgloss.Left,
-		title,
-		sep,
-		"",
-		list,
-		"",
-		hint,
-	)
-
-	borderColor := accentColor
-	if !m.historyFocused {
-		borderColor = dimColor
+	if m.diagnosis.ServerVersion != "" {
+		lines = append(lines, "", histNameStyle.Render(clip("Server   "+m.diagnosis.ServerVersion, maxW)))
 	}
-	hStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(borderColor).
-		Padding(1, 2)
-	return hStyle.Width(histPanelW).Render(inner)
+	if m.diagnosis.Latency > 0 {
+		lines = append(lines, histNameStyle.Render(fmt.Sprintf("Latency  %.1fms", float64(m.diagnosis.Latency.Microseconds())/1000)))
+	}
+	if m.diagnosis.OK() {
+		lines = append(lines, "", diagOKStyle.Render("Connection looks good"))
+	}
+	lines = append(lines, "")
+	return strings.Join(lines, "\n")
 }
@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+	"otto/db"
+)
+
+type databasesLoadedMsg struct {
+	names []string
+	err   error
+}
+
+type databaseSwitchedMsg struct {
+	name string
+	err  error
+}
+
+type databasePicker struct {
+	names     []string
+	filtered  []string
+	current   string
+	query     string
+	cursor    int
+	loading   bool
+	switching string
+	err       error
+}
+
+func newDatabasePicker(current string) *databasePicker {
+	return &databasePicker{current: current, loading: true}
+}
+
+func loadDatabases(d db.DB) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		names, err := d.ListDatabases(ctx)
+		return databasesLoadedMsg{names: names, err: err}
+	}
+}
+
+func switchDatabase(d db.DB, name string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return databaseSwitchedMsg{name: name, err: d.SwitchDatabase(ctx, name)}
+	}
+}
+
+func (p *databasePicker) setNames(names []string) {
+	p.names = names
+	p.loading = false
+	p.applyFilter()
+	for i, name := range p.filtered {
+		if name == p.current {
+			p.cursor = i
+		}
+	}
+}
+
+func (p *databasePicker) applyFilter() {
+	p.cursor = 0
+	if p.query == "" {
+		p.filtered = p.names
+		return
+	}
+	p.filtered = nil
+	for _, match := range fuzzy.Find(p.query, p.names) {
+		p.filtered = append(p.filtered, p.names[match.Index])
+	}
+}
+
+func (p *databasePicker) selected() string {
+	if p.cursor < 0 || p.cursor >= len(p.filtered) {
+		return ""
+	}
+	return p.filtered[p.cursor]
+}
+
+func (p *databasePicker) update(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyUp:
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case tea.KeyDown:
+		if p.cursor < len(p.filtered)-1 {
+			p.cursor++
+		}
+	case tea.KeyBackspace:
+		if p.query != "" {
+			r := []rune(p.query)
+			p.query = string(r[:len(r)-1])
+			p.applyFilter()
+		}
+	case tea.KeyRunes:
+		p.query += string(msg.Runes)
+		p.applyFilter()
+	}
+}
+
+var (
+	pickerBoxStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#FF6F61")).
+			Padding(0, 1)
+
+	pickerCurrentStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#3FB950"))
+)
+
+func (p *databasePicker) view(w, h int) string {
+	boxW := 40
+	if boxW > w-4 {
+		boxW = w - 4
+	}
+	listH := h - 8
+	if listH < 3 {
+		listH = 3
+	}
+
+	lines := []string{
+		sidebarFocusTitleStyle.Render("DATABASES"),
+		sidebarSearchLabelStyle.Render("/") + sidebarSearchStyle.Render(p.query+"█"),
+		"",
+	}
+	switch {
+	case p.loading:
+		lines = append(lines, sidebarItemStyle.Render("loading..."))
+	case p.switching != "":
+		lines = append(lines, healthWarnStyle.Render("⟳ switching to "+p.switching+"…"))
+	case len(p.filtered) == 0:
+		lines = append(lines, sidebarNoMatchStyle.Render("no match"))
+	default:
+		start := 0
+		if p.cursor >= listH {
+			start = p.cursor - listH + 1
+		}
+		end := min(start+listH, len(p.filtered))
+		for i := start; i < end; i++ {
+			name := clipRunes(p.filtered[i], boxW-6)
+			mark := "  "
+			if p.filtered[i] == p.current {
+				mark = pickerCurrentStyle.Render("● ")
+			}
+			if i == p.cursor {
+				lines = append(lines, mark+sidebarSelectedStyle.Render("▶ "+name))
+			} else {
+				lines = append(lines, mark+sidebarItemStyle.Render("  "+name))
+			}
+		}
+	}
+	if p.err != nil {
+		lines = append(lines, "", errStyle.Render(lipgloss.NewStyle().Width(boxW-4).Render("✕ "+p.err.Error())))
+	}
+	lines = append(lines, "", layoutFooter.Render("↑↓ move · Enter switch · Esc cancel"))
+
+	box := pickerBoxStyle.Width(boxW).Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(w, h, lipgloss.Center, lipgloss.Center, box)
+}
@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"otto/db"
+)
+
+type showDDLMsg struct {
+	object db.Object
+}
+
+type ddlLoadedMsg struct {
+	ddl string
+	err error
+}
+
+type openInEditorMsg struct {
+	query string
+}
+
+type closeDDLMsg struct{}
+
+type DDLModel struct {
+	db      db.DB
+	object  db.Object
+	ddl     string
+	lines   []string
+	err     error
+	loading bool
+	scroll  int
+	scrollX int
+	width   int
+	height  int
+}
+
+func NewDDLModel(d db.DB, o db.Object, width, height int) DDLModel {
+	return DDLModel{db: d, object: o, loading: true, width: width, height: height}
+}
+
+func (m DDLModel) Init() tea.Cmd {
+	return m.load
+}
+
+func (m DDLModel) load() tea.Msg {
+	ddl, err := m.db.ObjectDDL(context.Background(), m.object)
+	return ddlLoadedMsg{ddl: ddl, err: err}
+}
+
+func (m DDLModel) maxScroll() int {
+	return max(len(m.lines)-(m.height-2), 0)
+}
+
+func (m DDLModel) Update(msg tea.Msg) (DDLModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ddlLoadedMsg:
+		m.loading = false
+		m.err = msg.err
+		m.ddl = msg.ddl
+		m.lines = strings.Split(strings.ReplaceAll(strings.TrimRight(msg.ddl, "\n"), "\t", "    "), "\n")
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			return m, func() tea.Msg { return closeDDLMsg{} }
+		case "j", "down":
+			m.scroll = min(m.scroll+1, m.maxScroll())
+		case "k", "up":
+			m.scroll = max(m.scroll-1, 0)
+		case "pgdown", "ctrl+d":
+			m.scroll = min(m.scroll+m.height/2, m.maxScroll())
+		case "pgup", "ctrl+u":
+			m.scroll = max(m.scroll-m.height/2, 0)
+		case "l", "right":
+			m.scrollX += 5
+		case "h", "left":
+			m.scrollX = max(m.scrollX-5, 0)
+		case "r":
+			m.loading = true
+			return m, m.load
+		case "e":
+			if m.ddl != "" {
+				query := m.ddl
+				return m, func() tea.Msg { return openInEditorMsg{query: query} }
+			}
+		}
+	}
+	return m, nil
+}
+
+var ddlLineNoStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#555555"))
+
+func (m DDLModel) ViewPanel(w, h int) string {
+	var b strings.Builder
+	title := fmt.Sprintf(" %s %s", strings.ToUpper(string(m.object.Kind)), qualifiedName(m.object.Schema, m.object.Name))
+	b.WriteString(tblHeaderStyle.Render(clipLine(title, w)) + "\n\n")
+
+	switch {
+	case m.loading:
+		b.WriteString(" Loading...")
+		return b.String()
+	case m.err != nil:
+		errSty := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000"))
+		b.WriteString(errSty.Render(fmt.Sprintf(" Error: %v", m.err)))
+		return b.String()
+	}
+
+	gutter := len(fmt.Sprint(len(m.lines)))
+	visible := max(h-2, 1)
+	end := min(m.scroll+visible, len(m.lines))
+	for i := m.scroll; i < end; i++ {
+		num := ddlLineNoStyle.Render(fmt.Sprintf(" %*d ", gutter, i+1))
+		line := truncateLine(m.lines[i], m.scrollX, max(w-gutter-3, 1))
+		b.WriteString(num + " " + tblRowStyle.Render(line) + "\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -3,10 +3,12 @@ package ui
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"otto/db"
@@ -40,6 +42,7 @@ type columnsLoadedMsg struct {
 
 type EditorModel struct {
 	db          db.DB
+	cfg         db.Config
 	textarea    textarea.Model
 	mode        editorFocus
 	result      *db.QueryResult
@@ -47,17 +50,47 @@ type EditorModel struct {
 	err         error
 	running     bool
 	cursor      int
-	scrollX     int
+	colOffset   int
 	width       int
 	height      int
 	colWidths   []int
+	cols        columnLayout
+	search      *gridSearch
 	comp        completionModel
 	tables      []string
 	columns     map[string][]string
 	lowercaseKw bool
+	confirming  bool
+	guard       *guardPrompt
+
+	colCursor    int
+	record       bool
+	recordScroll int
+}
+
+type guardPrompt struct {
+	pending  []db.Destructive
+	input    textinput.Model
+	mismatch bool
 }
 
-func NewEditorModel(d db.DB, width, height int) EditorModel {
+func newGuardPrompt(pending []db.Destructive) *guardPrompt {
+	in := textinput.New()
+	in.Prompt = ""
+	in.CharLimit = 128
+	in.TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#E6EDF3"))
+	in.Focus()
+	return &guardPrompt{pending: pending, input: in}
+}
+
+func (g *guardPrompt) expected() string {
+	if obj := g.pending[0].Object; obj != "" {
+		return obj
+	}
+	return "yes"
+}
+
+func NewEditorModel(d db.DB, cfg db.Config, width, height int) EditorModel {
 	editorH := editorHeight(height)
 	ta := textarea.New()
 	ta.Placeholder = "SELECT * FROM ..."
@@ -71,6 +104,7 @@ func NewEditorModel(d db.DB, width, height int) EditorModel {
 
 	return EditorModel{
 		db:       d,
+		cfg:      cfg,
 		textarea: ta,
 		mode:     modeEditing,
 		width:    width,
@@ -178,27 +212,44 @@ func (m EditorModel) CompletionActive() bool {
 	return m.mode == modeEditing && m.comp.active
 }
 
-func (m *EditorModel) calcColWidths() {
-	if m.result == nil {
-		return
-	}
-	m.colWidths = make([]int, len(m.result.Columns))
-	for i, col := range m.result.Columns {
-		m.colWidths[i] = len([]rune(col))
-	}
-	for _, row := range m.result.Rows {
-		for i, val := range row {
-			if idx := strings.IndexAny(val, "\n\r"); idx >= 0 {
-				val = val[:idx]
-			}
-			if l := len([]rune(val)); l > m.colWidths[i] {
-				m.colWidths[i] = l
-			}
-			if m.colWidths[i] > 30 {
-				m.colWidths[i] = 30
-			}
+func (m EditorModel) guardedStatements() []db.Destructive {
+	guarded := m.cfg.GuardedHazards()
+	var pending []db.Destructive
+	for _, d := range db.FindDestructive(m.textarea.Value(), m.cfg.Driver) {
+		if guarded[d.Hazard] {
+			pending = append(pending, d)
 		}
 	}
+	return pending
+}
+
+func (m EditorModel) updateGuard(msg tea.KeyMsg) (EditorModel, tea.Cmd) {
+	g := m.guard
+	switch msg.Type {
+	case tea.KeyEsc:
+		db.LogGuardDecision(m.cfg, g.pending[0], "cancelled")
+		m.guard = nil
+		return m, nil
+	case tea.KeyEnter:
+		if !strings.EqualFold(strings.TrimSpace(g.input.Value()), g.expected()) {
+			g.mismatch = true
+			return m, nil
+		}
+		db.LogGuardDecision(m.cfg, g.pending[0], "confirmed")
+		g.pending = g.pending[1:]
+		g.input.Reset()
+		g.mismatch = false
+		if len(g.pending) > 0 {
+			return m, nil
+		}
+		m.guard = nil
+		m.running = true
+		return m, m.execQuery
+	}
+	var cmd tea.Cmd
+	g.input, cmd = g.input.Update(msg)
+	g.mismatch = false
+	return m, cmd
 }
 
 func (m EditorModel) execQuery() tea.Msg {
@@ -230,25 +281,86 @@ func (m EditorModel) Update(msg tea.Msg) (EditorModel, tea.Cmd) {
 		m.columns = msg.columns
 
 	case queryResultMsg:
+		m.search = nil
 		m.result = msg.result
 		m.elapsed = msg.elapsed
 		m.err = nil
 		m.cursor = 0
-		m.scrollX = 0
 		m.running = false
 		m.mode = modeResults
-		m.calcColWidths()
+		m.record = false
+		if !m.cols.sameColumns(m.result.Columns) {
+			m.cols = newColumnLayout(m.result.Columns, db.ColumnLayout{})
+			m.colCursor, m.colOffset = 0, 0
+		}
+		m.colCursor = m.cols.step(m.colCursor, 0)
+		m.colWidths = contentWidths(m.result)
+		m.followColumn()
 
 	case queryErrMsg:
+		m.search = nil
 		m.err = msg.err
 		m.result = nil
+		m.record = false
 		m.running = false
 		m.mode = modeResults
 
 	case tea.KeyMsg:
+		if m.guard != nil {
+			return m.updateGuard(msg)
+		}
+		if m.confirming {
+			switch msg.String() {
+			case "y", "Y":
+				m.confirming = false
+				m.running = true
+				return m, m.execQuery
+			case "n", "N", "esc":
+				m.confirming = false
+			}
+			return m, nil
+		}
+
+		if m.record && m.mode == modeResults {
+			return m.updateRecord(msg)
+		}
+		if m.search != nil && m.mode == modeResults {
+			if m.search.typing {
+				return m.updateSearch(msg)
+			}
+			switch msg.String() {
+			case "esc":
+				m.search = nil
+				return m, nil
+			case "n", "N":
+				delta := 1
+				if msg.String() == "N" {
+					delta = -1
+				}
+				m.search.step(delta)
+				m.jumpToMatch()
+				return m, nil
+			}
+		}
+
 		switch msg.String() {
 		case "ctrl+e":
 			if !m.running {
+				if m.cfg.ReadOnly {
+					if err := db.CheckReadOnly(m.textarea.Value(), m.cfg.Driver); err != nil {
+						m.err = err
+						m.result = nil
+						return m, nil
+					}
+				}
+				if pending := m.guardedStatements(); len(pending) > 0 {
+					m.guard = newGuardPrompt(pending)
+					return m, textinput.Blink
+				}
+				if m.cfg.Environment().Policy.ConfirmWrites && db.IsWriteStatement(m.textarea.Value(), m.cfg.Driver) {
+					m.confirming = true
+					return m, nil
+				}
 				m.running = true
 				return m, m.execQuery
 			}
@@ -300,6 +412,37 @@ func (m EditorModel) Update(msg tea.Msg) (EditorModel, tea.Cmd) {
 		}
 
 		switch msg.String() {
+		case "enter":
+			if m.result != nil && len(m.result.Rows) > 0 {
+				m.record = true
+				m.recordScroll = 0
+			}
+		case "/":
+			if m.result != nil {
+				m.search = newGridSearch(m.cursor, m.colCursor)
+				return m, m.search.input.Focus()
+			}
+		case "i":
+			return m, m.inspectCell()
+		case "l", "right", "d":
+			m.colCursor = m.cols.step(m.colCursor, 1)
+			m.followColumn()
+		case "h", "left", "a":
+			m.colCursor = m.cols.step(m.colCursor, -1)
+			m.followColumn()
+		case "home", "end":
+			if vis := m.cols.visible(); len(vis) > 0 {
+				m.colCursor = vis[0]
+				if msg.String() == "end" {
+					m.colCursor = vis[len(vis)-1]
+				}
+			}
+			m.followColumn()
+		case "<", ">", "x", "X", "P", "+", "-", "=", "0":
+			if m.result != nil && len(m.result.Columns) > 0 {
+				m.colCursor, _ = m.cols.handleKey(msg.String(), m.colCursor, m.colWidths[m.colCursor])
+				m.followColumn()
+			}
 		case "j", "down":
 			if m.result != nil && m.cursor < len(m.result.Rows)-1 {
 				m.cursor++
@@ -308,17 +451,17 @@ func (m EditorModel) Update(msg tea.Msg) (EditorModel, tea.Cmd) {
 			if m.cursor > 0 {
 				m.cursor--
 			}
-		case "l", "right":
-			m.scrollX += 5
-		case "h", "left":
-			if m.scrollX >= 5 {
-				m.scrollX -= 5
-			} else {
-				m.scrollX = 0
-			}
 		}
 
 	default:
+		if m.guard != nil {
+			var cmd tea.Cmd
+			m.guard.input, cmd = m.guard.input.Update(msg)
+			return m, cmd
+		}
+		if m.search != nil && m.search.typing {
+			return m.updateSearch(msg)
+		}
 		if m.mode == modeEditing {
 			var cmd tea.Cmd
 			m.textarea, cmd = m.textarea.Update(msg)
@@ -328,6 +471,103 @@ func (m EditorModel) Update(msg tea.Msg) (EditorModel, tea.Cmd) {
 	return m, nil
 }
 
+func (m EditorModel) gridColumns() ([]int, []string, []int) {
+	vis := m.cols.visible()
+	labels := make([]string, len(vis))
+	widths := make([]int, len(vis))
+	for k, i := range vis {
+		label := m.result.Columns[i]
+		if i == m.colCursor {
+			label = "[" + label + "]"
+		}
+		labels[k] = label
+		widths[k] = m.cols.displayWidth(i, m.colWidths[i], label)
+	}
+	return vis, labels, widths
+}
+
+// followColumn keeps the selected column on screen; the results grid is
+// four runes narrower than the panel because of the two nested borders.
+func (m *EditorModel) followColumn() {
+	if m.result == nil {
+		return
+	}
+	vis, _, widths := m.gridColumns()
+	m.colOffset = m.cols.follow(m.colOffset, slices.Index(vis, m.colCursor), widths, m.width-4)
+}
+
+func (m EditorModel) updateSearch(msg tea.Msg) (EditorModel, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.String() == "esc" {
+		m.cursor, m.colCursor = m.search.origin.row, m.search.origin.col
+		m.search = nil
+		m.followColumn()
+		return m, nil
+	}
+	cmd, changed, _ := m.search.update(msg, m.colCursor)
+	if changed {
+		m.search.find(m.result, m.cols.visible())
+		m.jumpToMatch()
+	}
+	return m, cmd
+}
+
+func (m *EditorModel) jumpToMatch() {
+	if g, ok := m.search.at(); ok {
+		m.cursor, m.colCursor = g.row, g.col
+		m.followColumn()
+	}
+}
+
+func (m EditorModel) inspectCell() tea.Cmd {
+	if m.result == nil || m.cursor >= len(m.result.Rows) || m.colCursor >= len(m.result.Columns) {
+		return nil
+	}
+	msg := showCellMsg{column: m.result.Columns[m.colCursor], value: m.result.Rows[m.cursor][m.colCursor]}
+	return func() tea.Msg { return msg }
+}
+
+func (m EditorModel) updateRecord(msg tea.KeyMsg) (EditorModel, tea.Cmd) {
+	maxScroll := max(recordLineCount(m.result.Columns, m.result.Rows[m.cursor], m.width)-(m.height-2), 0)
+	switch msg.String() {
+	case "esc", "q", "enter":
+		m.record = false
+	case "ctrl+r":
+		m.record = false
+		m.mode = modeEditing
+		m.textarea.Focus()
+		return m, textarea.Blink
+	case "j", "down":
+		m.recordScroll = min(m.recordScroll+1, maxScroll)
+	case "k", "up":
+		m.recordScroll = max(m.recordScroll-1, 0)
+	case "pgdown", "ctrl+d":
+		m.recordScroll = min(m.recordScroll+m.height/2, maxScroll)
+	case "pgup", "ctrl+u":
+		m.recordScroll = max(m.recordScroll-m.height/2, 0)
+	case "i":
+		return m, m.inspectCell()
+	case "d":
+		if m.colCursor < len(m.result.Columns)-1 {
+			m.colCursor++
+		}
+	case "a":
+		if m.colCursor > 0 {
+			m.colCursor--
+		}
+	case "n", "l", "right":
+		if m.cursor < len(m.result.Rows)-1 {
+			m.cursor++
+			m.recordScroll = 0
+		}
+	case "p", "h", "left":
+		if m.cursor > 0 {
+			m.cursor--
+			m.recordScroll = 0
+		}
+	}
+	return m, nil
+}
+
 var (
 	edBorderActive = lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
@@ -352,6 +592,10 @@ var (
 )
 
 func (m EditorModel) ViewPanel(w, h int) string {
+	if m.record && m.result != nil && m.cursor < len(m.result.Rows) {
+		title := tblHeaderStyle.Render(fmt.Sprintf(" Result row %d of %d", m.cursor+1, len(m.result.Rows)))
+		return renderRecord(title, m.result.Columns, m.result.Rows[m.cursor], m.colCursor, m.recordScroll, w, h)
+	}
 	edH := editorHeight(h)
 	innerW := w - 2
 
@@ -403,6 +647,21 @@ func (m EditorModel) ViewPanel(w, h int) string {
 
 	var statusLine string
 	switch {
+	case m.guard != nil:
+		d := m.guard.pending[0]
+		target := d.Target
+		if target == "" {
+			target = "statement"
+		}
+		prompt := fmt.Sprintf(" ⚠  %s on %s — type %s to confirm, Esc to cancel: ", d.Hazard.Describe(), target, m.guard.expected())
+		sty := edStatusRun
+		if m.guard.mismatch {
+			sty = edStatusErr
+		}
+		statusLine = sty.Render(prompt) + m.guard.input.View()
+	case m.confirming:
+		env := strings.ToUpper(m.cfg.Environment().Name)
+		statusLine = edStatusRun.Render(" ⚠  Write statement on " + env + " — y run  ·  n cancel")
 	case m.running:
 		statusLine = edStatusRun.Render(" ⟳  Running...")
 	case m.err != nil:
@@ -412,8 +671,19 @@ func (m EditorModel) ViewPanel(w, h int) string {
 		}
 		statusLine = edStatusErr.Render(" ✗  " + msg)
 	case m.result != nil:
-		statusLine = edStatusOk.Render(fmt.Sprintf(" ✓  %d rows  (%dms)",
-			len(m.result.Rows), m.elapsed.Milliseconds()))
+		info := m.cols.describe()
+		if vis, _, widths := m.gridColumns(); len(vis) > 0 {
+			first := m.cols.follow(m.colOffset, slices.Index(vis, m.colCursor), widths, w-4)
+			info += m.cols.shownRange(first, widths, w-4)
+		}
+		if m.search != nil {
+			info += m.search.status(m.result.Columns)
+		}
+		statusLine = edStatusOk.Render(fmt.Sprintf(" ✓  %d rows  (%dms)%s",
+			len(m.result.Rows), m.elapsed.Milliseconds(), info))
+		if m.search != nil && m.search.typing {
+			statusLine += "  " + m.search.input.View()
+		}
 	default:
 		statusLine = edHintStyle.Render(" ─  no results yet")
 	}
@@ -462,18 +732,17 @@ func (m EditorModel) renderResults(w, h int) string {
 
 	var b strings.Builder
 
-	var headerCells, separators []string
-	for i, col := range m.result.Columns {
-		cw := m.colWidths[i]
-		headerCells = append(headerCells, padRight(col, cw))
-		separators = append(separators, strings.Repeat("─", cw))
+	vis, labels, widths := m.gridColumns()
+	first := m.cols.follow(m.colOffset, slices.Index(vis, m.colCursor), widths, w)
+	headerCells := make([]string, len(vis))
+	separators := make([]string, len(vis))
+	for k, label := range labels {
+		headerCells[k] = padRight(label, widths[k])
+		separators[k] = strings.Repeat("─", widths[k])
 	}
 
-	headerLine := "│ " + strings.Join(headerCells, " │ ") + " │"
-	sepLine := "├─" + strings.Join(separators, "─┼─") + "─┤"
-
-	b.WriteString(tblHeaderStyle.Render(clipLine(truncateLine(headerLine, m.scrollX, w), w)) + "\n")
-	b.WriteString(tblBorderStyle.Render(clipLine(truncateLine(sepLine, m.scrollX, w), w)) + "\n")
+	b.WriteString(tblHeaderStyle.Render(m.cols.gridLine(headerCells, gridCellBorders, first, w)) + "\n")
+	b.WriteString(tblBorderStyle.Render(m.cols.gridLine(separators, gridSepBorders, first, w)) + "\n")
 
 	viewStart := 0
 	if m.cursor >= visibleRows {
@@ -486,16 +755,23 @@ func (m EditorModel) renderResults(w, h int) string {
 
 	for i := viewStart; i < endRow; i++ {
 		row := m.result.Rows[i]
-		var cells []string
-		for j, val := range row {
-			cells = append(cells, padRight(val, m.colWidths[j]))
+		cells := make([]string, len(vis))
+		for k, j := range vis {
+			cells[k] = padRight(row[j], widths[k])
 		}
-		line := "│ " + strings.Join(cells, " │ ") + " │"
-		line = clipLine(truncateLine(line, m.scrollX, w), w)
+		line := m.cols.gridLine(cells, gridCellBorders, first, w)
+		style := tblRowStyle
 		if i == m.cursor {
-			b.WriteString(tblSelStyle.Render(line) + "\n")
+			style = tblSelStyle
+		}
+		if m.search != nil && m.search.query() != "" {
+			current := -1
+			if g, ok := m.search.at(); ok && g.row == i {
+				current = g.col
+			}
+			b.WriteString(m.search.highlight(line, style, cells, m.cols.cellOffsets(cells, first, 0), vis, current) + "\n")
 		} else {
-			b.WriteString(tblRowStyle.Render(line) + "\n")
+			b.WriteString(style.Render(line) + "\n")
 		}
 	}
 
@@ -0,0 +1,403 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"otto/db"
+)
+
+type erdColumn struct {
+	name string
+	typ  string
+	pk   bool
+	fk   bool
+}
+
+type erdTable struct {
+	schema  string
+	name    string
+	columns []erdColumn
+}
+
+type erdGraph struct {
+	schema string
+	title  string
+	tables []*erdTable
+	fks    []db.ForeignKey
+}
+
+func (g *erdGraph) table(schema, name string) *erdTable {
+	for _, t := range g.tables {
+		if t.schema == schema && t.name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+func (g *erdGraph) add(schema, name string) *erdTable {
+	if t := g.table(schema, name); t != nil {
+		return t
+	}
+	t := &erdTable{schema: schema, name: name}
+	g.tables = append(g.tables, t)
+	return t
+}
+
+func newERDGraph(schema, title string, tables []db.Object, columns []db.Column, fks []db.ForeignKey) *erdGraph {
+	g := &erdGraph{schema: schema, title: title}
+	for _, o := range tables {
+		g.add(o.Schema, o.Name)
+	}
+	for _, fk := range fks {
+		g.add(fk.Schema, fk.Table)
+		g.add(fk.RefSchema, fk.RefTable)
+	}
+	for _, c := range columns {
+		if t := g.table(c.Schema, c.Table); t != nil {
+			t.columns = append(t.columns, erdColumn{name: c.Name, typ: c.DataType, pk: c.PrimaryKey})
+		}
+	}
+	for _, fk := range fks {
+		t := g.table(fk.Schema, fk.Table)
+		for i := range t.columns {
+			if slices.Contains(fk.Columns, t.columns[i].name) {
+				t.columns[i].fk = true
+			}
+		}
+	}
+	g.fks = fks
+	return g
+}
+
+type erdLoadedMsg struct {
+	graph *erdGraph
+	err   error
+}
+
+type showERDMsg struct {
+	schema string
+	table  string
+}
+
+type closeERDMsg struct{}
+
+func loadSchemaERD(d db.DB, schema string) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		tables, err := d.ListBaseTables(ctx, schema)
+		if err != nil {
+			return erdLoadedMsg{err: err}
+		}
+		columns, err := d.ListSchemaColumns(ctx, schema)
+		if err != nil {
+			return erdLoadedMsg{err: err}
+		}
+		fks, err := d.ListSchemaForeignKeys(ctx, schema)
+		if err != nil {
+			return erdLoadedMsg{err: err}
+		}
+		title := fmt.Sprintf("schema %s", schema)
+		return erdLoadedMsg{graph: newERDGraph(schema, title, tables, columns, fks)}
+	}
+}
+
+func loadTableERD(d db.DB, schema, table string) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		fks, err := d.ListForeignKeys(ctx, schema, table)
+		if err != nil {
+			return erdLoadedMsg{err: err}
+		}
+		referencing, err := d.ListReferencingKeys(ctx, schema, table)
+		if err != nil {
+			return erdLoadedMsg{err: err}
+		}
+		for _, fk := range referencing {
+			if fk.Schema != schema || fk.Table != table {
+				fks = append(fks, fk)
+			}
+		}
+		g := newERDGraph(schema, "", []db.Object{{Schema: schema, Name: table}}, nil, fks)
+		var columns []db.Column
+		for _, t := range g.tables {
+			cols, err := d.ListTableColumns(ctx, t.schema, t.name)
+			if err != nil {
+				return erdLoadedMsg{err: err}
+			}
+			columns = append(columns, cols...)
+		}
+		title := fmt.Sprintf("%s and its foreign keys", qualifiedName(schema, table))
+		return erdLoadedMsg{graph: newERDGraph(schema, title, []db.Object{{Schema: schema, Name: table}}, columns, fks)}
+	}
+}
+
+var erdIdentRe = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+func (g *erdGraph) entityName(t *erdTable) string {
+	name := t.name
+	if t.schema != g.schema {
+		name = t.schema + "_" + t.name
+	}
+	return erdIdentRe.ReplaceAllString(name, "_")
+}
+
+func (g *erdGraph) mermaid() string {
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+	for _, t := range g.tables {
+		fmt.Fprintf(&b, "    %s {\n", g.entityName(t))
+		for _, c := range t.columns {
+			var keys []string
+			if c.pk {
+				keys = append(keys, "PK")
+			}
+			if c.fk {
+				keys = append(keys, "FK")
+			}
+			typ := strings.Trim(erdIdentRe.ReplaceAllString(shortTypeName(c.typ), "_"), "_")
+			if typ == "" {
+				typ = "unknown"
+			}
+			line := fmt.Sprintf("        %s %s %s", typ, erdIdentRe.ReplaceAllString(c.name, "_"), strings.Join(keys, ", "))
+			b.WriteString(strings.TrimRight(line, " ") + "\n")
+		}
+		b.WriteString("    }\n")
+	}
+	for _, fk := range g.fks {
+		src, dst := g.table(fk.Schema, fk.Table), g.table(fk.RefSchema, fk.RefTable)
+		fmt.Fprintf(&b, "    %s }o--|| %s : %q\n", g.entityName(src), g.entityName(dst), fk.Name)
+	}
+	return b.String()
+}
+
+func (g *erdGraph) dot() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", g.schema)
+	b.WriteString("    graph [rankdir=RL];\n")
+	b.WriteString("    node [shape=plaintext, fontname=\"Helvetica\"];\n")
+	for _, t := range g.tables {
+		fmt.Fprintf(&b, "    %q [label=<<table border=\"0\" cellborder=\"1\" cellspacing=\"0\">", qualifiedName(t.schema, t.name))
+		fmt.Fprintf(&b, "<tr><td bgcolor=\"#dddddd\"><b>%s</b></td></tr>", html.EscapeString(t.name))
+		for _, c := range t.columns {
+			marker := ""
+			if c.pk {
+				marker = " (PK)"
+			}
+			fmt.Fprintf(&b, "<tr><td port=\"%s\" align=\"left\">%s%s <i>%s</i></td></tr>",
+				html.EscapeString(c.name), html.EscapeString(c.name), marker, html.EscapeString(c.typ))
+		}
+		b.WriteString("</table>>];\n")
+	}
+	for _, fk := range g.fks {
+		fmt.Fprintf(&b, "    %q:%q -> %q:%q [label=%q];\n",
+			qualifiedName(fk.Schema, fk.Table), firstOr(fk.Columns),
+			qualifiedName(fk.RefSchema, fk.RefTable), firstOr(fk.RefColumns), fk.Name)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+type erdFormat int
+
+const (
+	erdDiagram erdFormat = iota
+	erdMermaid
+	erdDOT
+)
+
+var erdFormatNames = []string{"Diagram", "Mermaid", "DOT"}
+
+var erdFormatExts = []string{".txt", ".mmd", ".dot"}
+
+type ERDModel struct {
+	db         db.DB
+	schema     string
+	table      string
+	graph      *erdGraph
+	canvas     *canvas
+	err        error
+	loading    bool
+	format     erdFormat
+	allColumns bool
+	text       []string
+	notice     string
+	scroll     int
+	scrollX    int
+	width      int
+	height     int
+}
+
+func NewERDModel(d db.DB, schema, table string, width, height int) ERDModel {
+	return ERDModel{db: d, schema: schema, table: table, loading: true, width: width, height: height}
+}
+
+func (m ERDModel) Init() tea.Cmd {
+	return m.load()
+}
+
+func (m ERDModel) load() tea.Cmd {
+	if m.table != "" {
+		return loadTableERD(m.db, m.schema, m.table)
+	}
+	return loadSchemaERD(m.db, m.schema)
+}
+
+func (m *ERDModel) build() {
+	m.canvas, m.text = nil, nil
+	if m.graph == nil {
+		return
+	}
+	switch m.format {
+	case erdDiagram:
+		m.canvas = layoutERD(m.graph, m.allColumns)
+	case erdMermaid:
+		m.text = strings.Split(strings.TrimRight(m.graph.mermaid(), "\n"), "\n")
+	case erdDOT:
+		m.text = strings.Split(strings.TrimRight(m.graph.dot(), "\n"), "\n")
+	}
+}
+
+func (m ERDModel) contentSize() (int, int) {
+	if m.canvas != nil && len(m.canvas.runes) > 0 {
+		return len(m.canvas.runes[0]), len(m.canvas.runes)
+	}
+	w := 0
+	for _, l := range m.text {
+		w = max(w, len([]rune(l)))
+	}
+	return w, len(m.text)
+}
+
+func (m ERDModel) export() string {
+	switch m.format {
+	case erdMermaid:
+		return m.graph.mermaid()
+	case erdDOT:
+		return m.graph.dot()
+	}
+	var lines []string
+	for _, row := range m.canvas.runes {
+		lines = append(lines, strings.TrimRight(string(row), " "))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func (m ERDModel) Update(msg tea.Msg) (ERDModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case erdLoadedMsg:
+		m.loading = false
+		m.err = msg.err
+		m.graph = msg.graph
+		m.build()
+	case tea.KeyMsg:
+		m.notice = ""
+		cw, ch := m.contentSize()
+		maxY, maxX := max(ch-(m.height-2), 0), max(cw-m.width, 0)
+		switch msg.String() {
+		case "esc", "q":
+			return m, func() tea.Msg { return closeERDMsg{} }
+		case "j", "down":
+			m.scroll = min(m.scroll+1, maxY)
+		case "k", "up":
+			m.scroll = max(m.scroll-1, 0)
+		case "pgdown", "ctrl+d":
+			m.scroll = min(m.scroll+m.height/2, maxY)
+		case "pgup", "ctrl+u":
+			m.scroll = max(m.scroll-m.height/2, 0)
+		case "l", "right":
+			m.scrollX = min(m.scrollX+8, maxX)
+		case "h", "left":
+			m.scrollX = max(m.scrollX-8, 0)
+		case "a":
+			if m.format == erdDiagram {
+				m.allColumns = !m.allColumns
+				m.build()
+			}
+		case "m":
+			m.format = (m.format + 1) % erdFormat(len(erdFormatNames))
+			m.scroll, m.scrollX = 0, 0
+			m.build()
+		case "w":
+			if m.graph == nil {
+				break
+			}
+			name := m.schema
+			if m.table != "" {
+				name += "." + m.table
+			}
+			path := erdIdentRe.ReplaceAllString(name, "_") + erdFormatExts[m.format]
+			if err := os.WriteFile(path, []byte(m.export()), 0644); err != nil {
+				m.notice = err.Error()
+			} else {
+				m.notice = "wrote " + path
+			}
+		case "e":
+			if m.format != erdDiagram && m.graph != nil {
+				query := m.export()
+				return m, func() tea.Msg { return openInEditorMsg{query: query} }
+			}
+		case "r":
+			m.loading = true
+			return m, m.load()
+		}
+	}
+	return m, nil
+}
+
+var erdNoticeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#3FB950"))
+
+func (m ERDModel) ViewPanel(w, h int) string {
+	var b strings.Builder
+	formats := make([]string, len(erdFormatNames))
+	for i, name := range erdFormatNames {
+		if erdFormat(i) == m.format {
+			formats[i] = tblTabActive.Render(name)
+		} else {
+			formats[i] = tblTabStyle.Render(name)
+		}
+	}
+	title := tblHeaderStyle.Render(" ER ") + strings.Join(formats, tblTabStyle.Render(" │ "))
+	if m.graph != nil {
+		info := fmt.Sprintf("  %s · %d tables · %d foreign keys", m.graph.title, len(m.graph.tables), len(m.graph.fks))
+		title += tblTabStyle.Render(clipLine(info, max(w-lipgloss.Width(title), 0)))
+	}
+	b.WriteString(title + "\n")
+	if m.notice != "" {
+		b.WriteString(erdNoticeStyle.Render(" "+m.notice) + "\n")
+	} else {
+		b.WriteString("\n")
+	}
+
+	switch {
+	case m.loading:
+		b.WriteString(" Loading...")
+		return b.String()
+	case m.err != nil:
+		errSty := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000"))
+		b.WriteString(errSty.Render(fmt.Sprintf(" Error: %v", m.err)))
+		return b.String()
+	case len(m.graph.tables) == 0:
+		b.WriteString(sidebarNoMatchStyle.Render(" no tables"))
+		return b.String()
+	}
+
+	visible := max(h-2, 1)
+	if m.canvas != nil {
+		b.WriteString(strings.Join(m.canvas.render(m.scrollX, m.scroll, w, visible), "\n"))
+		return b.String()
+	}
+	end := min(m.scroll+visible, len(m.text))
+	for _, line := range m.text[m.scroll:end] {
+		b.WriteString(tblRowStyle.Render(clipLine(truncateLine(line, m.scrollX, w), w)) + "\n")
+	}
+	return b.String()
+}
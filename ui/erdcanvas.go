@@ -0,0 +1,416 @@
+package ui
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"otto/db"
+)
+
+const (
+	lineUp = 1 << iota
+	lineDown
+	lineLeft
+	lineRight
+)
+
+var lineRunes = map[uint8]rune{
+	lineLeft: '─', lineRight: '─', lineLeft | lineRight: '─',
+	lineUp: '│', lineDown: '│', lineUp | lineDown: '│',
+	lineDown | lineRight: '┌', lineDown | lineLeft: '┐',
+	lineUp | lineRight: '└', lineUp | lineLeft: '┘',
+	lineUp | lineDown | lineRight: '├', lineUp | lineDown | lineLeft: '┤',
+	lineDown | lineLeft | lineRight: '┬', lineUp | lineLeft | lineRight: '┴',
+	lineUp | lineDown | lineLeft | lineRight: '┼',
+}
+
+type cellClass uint8
+
+const (
+	cellBlank cellClass = iota
+	cellLine
+	cellBorder
+	cellTitle
+	cellColumn
+	cellKey
+	cellType
+)
+
+var erdStyles = map[cellClass]lipgloss.Style{
+	cellBlank:  tblRowStyle,
+	cellLine:   lipgloss.NewStyle().Foreground(lipgloss.Color("#8B949E")),
+	cellBorder: lipgloss.NewStyle().Foreground(lipgloss.Color("#30363D")),
+	cellTitle:  tblHeaderStyle,
+	cellColumn: tblRowStyle,
+	cellKey:    lipgloss.NewStyle().Foreground(lipgloss.Color("#E3B341")),
+	cellType:   lipgloss.NewStyle().Foreground(lipgloss.Color("#6E7681")),
+}
+
+type canvas struct {
+	runes [][]rune
+	class [][]cellClass
+	lines [][]uint8
+}
+
+func newCanvas(w, h int) *canvas {
+	c := &canvas{runes: make([][]rune, h), class: make([][]cellClass, h), lines: make([][]uint8, h)}
+	for y := range h {
+		c.runes[y] = []rune(strings.Repeat(" ", w))
+		c.class[y] = make([]cellClass, w)
+		c.lines[y] = make([]uint8, w)
+	}
+	return c
+}
+
+func (c *canvas) set(x, y int, r rune, cls cellClass) {
+	if y >= 0 && y < len(c.runes) && x >= 0 && x < len(c.runes[y]) {
+		c.runes[y][x] = r
+		c.class[y][x] = cls
+	}
+}
+
+func (c *canvas) text(x, y int, s string, cls cellClass) {
+	for i, r := range []rune(s) {
+		c.set(x+i, y, r, cls)
+	}
+}
+
+func (c *canvas) mark(x, y int, bits uint8) {
+	if y >= 0 && y < len(c.lines) && x >= 0 && x < len(c.lines[y]) {
+		c.lines[y][x] |= bits
+	}
+}
+
+func (c *canvas) path(pts ...[2]int) {
+	for i := 1; i < len(pts); i++ {
+		x1, y1, x2, y2 := pts[i-1][0], pts[i-1][1], pts[i][0], pts[i][1]
+		switch {
+		case y1 == y2 && x1 != x2:
+			lo, hi := min(x1, x2), max(x1, x2)
+			c.mark(lo, y1, lineRight)
+			c.mark(hi, y1, lineLeft)
+			for x := lo + 1; x < hi; x++ {
+				c.mark(x, y1, lineLeft|lineRight)
+			}
+		case x1 == x2 && y1 != y2:
+			lo, hi := min(y1, y2), max(y1, y2)
+			c.mark(x1, lo, lineDown)
+			c.mark(x1, hi, lineUp)
+			for y := lo + 1; y < hi; y++ {
+				c.mark(x1, y, lineUp|lineDown)
+			}
+		}
+	}
+}
+
+func (c *canvas) flushLines() {
+	for y, row := range c.lines {
+		for x, bits := range row {
+			if bits != 0 {
+				c.set(x, y, lineRunes[bits], cellLine)
+			}
+		}
+	}
+}
+
+func (c *canvas) render(x0, y0, w, h int) []string {
+	var out []string
+	for y := y0; y < min(y0+h, len(c.runes)); y++ {
+		var b strings.Builder
+		row, cls := c.runes[y], c.class[y]
+		end := min(x0+w, len(row))
+		for x := x0; x < end; {
+			run := x
+			for run < end && cls[run] == cls[x] {
+				run++
+			}
+			b.WriteString(erdStyles[cls[x]].Render(string(row[x:run])))
+			x = run
+		}
+		out = append(out, b.String())
+	}
+	return out
+}
+
+type erdBox struct {
+	table *erdTable
+	lines []erdColumn
+	layer int
+	x, y  int
+	w, h  int
+	nameW int
+	typeW int
+}
+
+func newERDBox(t *erdTable, allColumns bool) *erdBox {
+	b := &erdBox{table: t, w: len([]rune(t.name)) + 4}
+	for _, c := range t.columns {
+		if allColumns || c.pk || c.fk {
+			b.lines = append(b.lines, c)
+			b.nameW = max(b.nameW, len([]rune(c.name)))
+			b.typeW = max(b.typeW, len([]rune(shortTypeName(c.typ))))
+		}
+	}
+	b.h = 3
+	if len(b.lines) > 0 {
+		b.h += len(b.lines) + 1
+		b.w = max(b.w, b.nameW+b.typeW+8)
+	}
+	return b
+}
+
+func (b *erdBox) rowOf(column string) int {
+	for i, c := range b.lines {
+		if c.name == column {
+			return b.y + 3 + i
+		}
+	}
+	return b.y + 1
+}
+
+func (b *erdBox) draw(c *canvas) {
+	right, bottom := b.x+b.w-1, b.y+b.h-1
+	c.set(b.x, b.y, '╭', cellBorder)
+	c.set(right, b.y, '╮', cellBorder)
+	c.set(b.x, bottom, '╰', cellBorder)
+	c.set(right, bottom, '╯', cellBorder)
+	for x := b.x + 1; x < right; x++ {
+		c.set(x, b.y, '─', cellBorder)
+		c.set(x, bottom, '─', cellBorder)
+	}
+	for y := b.y + 1; y < bottom; y++ {
+		c.set(b.x, y, '│', cellBorder)
+		c.set(right, y, '│', cellBorder)
+	}
+	c.text(b.x+2, b.y+1, b.table.name, cellTitle)
+	if len(b.lines) == 0 {
+		return
+	}
+	c.set(b.x, b.y+2, '├', cellBorder)
+	c.set(right, b.y+2, '┤', cellBorder)
+	for x := b.x + 1; x < right; x++ {
+		c.set(x, b.y+2, '─', cellBorder)
+	}
+	for i, col := range b.lines {
+		y := b.y + 3 + i
+		marker := " "
+		switch {
+		case col.pk:
+			marker = "⚷"
+		case col.fk:
+			marker = "→"
+		}
+		c.text(b.x+2, y, marker, cellKey)
+		c.text(b.x+4, y, col.name, cellColumn)
+		c.text(b.x+4+b.nameW+2, y, shortTypeName(col.typ), cellType)
+	}
+}
+
+type erdEdge struct {
+	src, dst     *erdBox
+	srcY, dstY   int
+	srcLeft      bool
+	dstLeft      bool
+	gap1, gap2   int
+	lane1, lane2 int
+	channel      int
+}
+
+// layoutERD places tables in layers so that every table sits to the right of
+// the tables it references, then routes foreign keys through the gaps
+// between layers and, when they span more than one gap, a channel row below
+// the boxes.
+func layoutERD(g *erdGraph, allColumns bool) *canvas {
+	boxes := make(map[*erdTable]*erdBox, len(g.tables))
+	for _, t := range g.tables {
+		boxes[t] = newERDBox(t, allColumns)
+	}
+	type link struct {
+		src, dst *erdBox
+		fk       db.ForeignKey
+	}
+	var links []link
+	connected := map[*erdBox]bool{}
+	for _, fk := range g.fks {
+		src, dst := boxes[g.table(fk.Schema, fk.Table)], boxes[g.table(fk.RefSchema, fk.RefTable)]
+		if src == nil || dst == nil {
+			continue
+		}
+		links = append(links, link{src, dst, fk})
+		connected[src], connected[dst] = true, true
+	}
+
+	n := len(g.tables)
+	for range n {
+		changed := false
+		for _, l := range links {
+			if l.src != l.dst && l.src.layer <= l.dst.layer && l.dst.layer+1 < n {
+				l.src.layer = l.dst.layer + 1
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	var layers [][]*erdBox
+	for _, t := range g.tables {
+		b := boxes[t]
+		if !connected[b] {
+			continue
+		}
+		for len(layers) <= b.layer {
+			layers = append(layers, nil)
+		}
+		layers[b.layer] = append(layers[b.layer], b)
+	}
+	layers = slices.DeleteFunc(layers, func(l []*erdBox) bool { return len(l) == 0 })
+
+	height := 0
+	for i, layer := range layers {
+		if i > 0 {
+			center := func(b *erdBox) float64 {
+				sum, k := 0.0, 0
+				for _, l := range links {
+					if l.src == b && l.dst.layer < b.layer {
+						sum += float64(l.dst.y + l.dst.h/2)
+						k++
+					}
+				}
+				if k == 0 {
+					return 1e9
+				}
+				return sum / float64(k)
+			}
+			slices.SortStableFunc(layer, func(a, b *erdBox) int {
+				return cmp.Compare(center(a), center(b))
+			})
+		}
+		y := 0
+		for _, b := range layer {
+			b.layer, b.y = i, y
+			y += b.h + 1
+		}
+		height = max(height, y)
+	}
+
+	// Tables without foreign keys are packed into extra columns on the right.
+	var loose []*erdBox
+	for _, t := range g.tables {
+		if b := boxes[t]; !connected[b] {
+			loose = append(loose, b)
+		}
+	}
+	limit := max(height, 24)
+	for i := 0; i < len(loose); {
+		var col []*erdBox
+		y := 0
+		for ; i < len(loose) && (y == 0 || y+loose[i].h <= limit); i++ {
+			b := loose[i]
+			b.layer, b.y = len(layers), y
+			y += b.h + 1
+			col = append(col, b)
+		}
+		layers = append(layers, col)
+		height = max(height, y)
+	}
+
+	var edges []*erdEdge
+	lanes := make([]int, len(layers)+1)
+	channels := 0
+	for _, l := range links {
+		e := &erdEdge{src: l.src, dst: l.dst}
+		e.srcY, e.dstY = l.src.rowOf(firstOr(l.fk.Columns)), l.dst.rowOf(firstOr(l.fk.RefColumns))
+		switch {
+		case l.src == l.dst:
+			e.gap1, e.gap2 = l.src.layer+1, l.src.layer+1
+		case l.dst.layer < l.src.layer:
+			e.srcLeft, e.dstLeft = true, false
+			e.gap1, e.gap2 = l.src.layer, l.dst.layer+1
+		default:
+			e.srcLeft, e.dstLeft = false, true
+			e.gap1, e.gap2 = l.src.layer+1, l.dst.layer
+		}
+		e.lane1 = lanes[e.gap1]
+		lanes[e.gap1]++
+		e.lane2 = e.lane1
+		if e.gap2 != e.gap1 {
+			e.lane2 = lanes[e.gap2]
+			lanes[e.gap2]++
+			e.channel = height + channels
+			channels++
+		}
+		edges = append(edges, e)
+	}
+
+	gapX := make([]int, len(layers)+1)
+	x := 0
+	for i := range gapX {
+		gapX[i] = x
+		switch {
+		case lanes[i] > 0:
+			x += 2*lanes[i] + 3
+		case i == 0 || i == len(layers):
+			x++
+		default:
+			x += 4
+		}
+		if i < len(layers) {
+			w := 0
+			for _, b := range layers[i] {
+				b.x = x
+				w = max(w, b.w)
+			}
+			x += w
+		}
+	}
+
+	c := newCanvas(x, height+channels)
+	laneX := func(gap, lane int) int { return gapX[gap] + 2 + 2*lane }
+	side := func(b *erdBox, left bool) int {
+		if left {
+			return b.x - 1
+		}
+		return b.x + b.w
+	}
+	for _, e := range edges {
+		sx, tx := side(e.src, e.srcLeft), side(e.dst, e.dstLeft)
+		x1 := laneX(e.gap1, e.lane1)
+		if e.gap1 == e.gap2 {
+			c.path([2]int{sx, e.srcY}, [2]int{x1, e.srcY}, [2]int{x1, e.dstY}, [2]int{tx, e.dstY})
+			continue
+		}
+		x2 := laneX(e.gap2, e.lane2)
+		c.path([2]int{sx, e.srcY}, [2]int{x1, e.srcY}, [2]int{x1, e.channel},
+			[2]int{x2, e.channel}, [2]int{x2, e.dstY}, [2]int{tx, e.dstY})
+	}
+	c.flushLines()
+	for _, layer := range layers {
+		for _, b := range layer {
+			b.draw(c)
+		}
+	}
+	for _, e := range edges {
+		if e.srcLeft {
+			c.set(e.src.x, e.srcY, '┤', cellBorder)
+		} else {
+			c.set(e.src.x+e.src.w-1, e.srcY, '├', cellBorder)
+		}
+		if e.dstLeft {
+			c.set(e.dst.x-1, e.dstY, '▶', cellLine)
+		} else {
+			c.set(e.dst.x+e.dst.w, e.dstY, '◀', cellLine)
+		}
+	}
+	return c
+}
+
+func firstOr(s []string) string {
+	if len(s) == 0 {
+		return ""
+	}
+	return s[0]
+}
@@ -0,0 +1,299 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"otto/db"
+)
+
+const finderMaxHits = 500
+
+type closeFinderMsg struct{}
+
+type finderColumnsMsg struct {
+	columns []db.Column
+	err     error
+}
+
+type finderEventMsg struct {
+	session int
+	search  int
+	event   db.ValueSearchEvent
+}
+
+type finderDoneMsg struct {
+	session int
+	search  int
+}
+
+type openValueHitMsg struct {
+	hit db.ValueHit
+}
+
+type FinderModel struct {
+	db      db.DB
+	cfg     db.Config
+	input   textinput.Model
+	columns []db.Column
+	loading bool
+	queued  bool
+	err     error
+
+	session   int
+	search    int
+	cancel    context.CancelFunc
+	events    chan db.ValueSearchEvent
+	running   bool
+	value     string
+	total     int
+	searched  int
+	failed    []db.ValueSearchEvent
+	hits      []db.ValueHit
+	truncated bool
+	cursor    int
+
+	width  int
+	height int
+}
+
+func NewFinderModel(d db.DB, cfg db.Config, width, height, session int) FinderModel {
+	in := textinput.New()
+	in.Prompt = "Find: "
+	in.Placeholder = "value, e.g. an email or an order id"
+	in.CharLimit = 256
+	in.Focus()
+	return FinderModel{db: d, cfg: cfg, input: in, loading: true, session: session, width: width, height: height}
+}
+
+func (m FinderModel) Init() tea.Cmd {
+	return tea.Batch(m.loadColumns, textinput.Blink)
+}
+
+func (m FinderModel) loadColumns() tea.Msg {
+	cols, err := m.db.ListColumns(context.Background())
+	return finderColumnsMsg{columns: cols, err: err}
+}
+
+func (m FinderModel) start() (FinderModel, tea.Cmd) {
+	value := strings.TrimSpace(m.input.Value())
+	if value == "" {
+		return m, nil
+	}
+	if m.loading {
+		m.queued = true
+		return m, nil
+	}
+	m.stop()
+	targets := db.PlanValueSearch(m.columns, value)
+	m.search++
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel, m.events = cancel, make(chan db.ValueSearchEvent)
+	m.value, m.total, m.searched = value, len(targets), 0
+	m.failed, m.hits, m.truncated, m.cursor = nil, nil, false, 0
+	m.running = true
+	m.input.Blur()
+	go db.SearchValue(ctx, m.cfg, targets, value, db.DefaultValueSearch, m.events)
+	return m, m.next()
+}
+
+func (m FinderModel) next() tea.Cmd {
+	session, search, events := m.session, m.search, m.events
+	return func() tea.Msg {
+		ev, ok := <-events
+		if !ok {
+			return finderDoneMsg{session: session, search: search}
+		}
+		return finderEventMsg{session: session, search: search, event: ev}
+	}
+}
+
+func (m *FinderModel) stop() {
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+	m.running = false
+}
+
+func (m FinderModel) Update(msg tea.Msg) (FinderModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case finderColumnsMsg:
+		m.loading = false
+		m.columns, m.err = msg.columns, msg.err
+		if m.queued && m.err == nil {
+			m.queued = false
+			return m.start()
+		}
+		return m, nil
+
+	case finderEventMsg:
+		if msg.session != m.session || msg.search != m.search || !m.running {
+			return m, nil
+		}
+		m.searched++
+		if msg.event.Err != nil {
+			m.failed = append(m.failed, msg.event)
+		}
+		m.hits = append(m.hits, msg.event.Hits...)
+		if len(m.hits) >= finderMaxHits {
+			m.hits = m.hits[:finderMaxHits]
+			m.truncated = true
+			m.stop()
+			return m, nil
+		}
+		return m, m.next()
+
+	case finderDoneMsg:
+		if msg.session == m.session && msg.search == m.search {
+			m.stop()
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.input.Focused() {
+			switch msg.String() {
+			case "enter":
+				return m.start()
+			case "esc":
+				if m.hits == nil && !m.running {
+					m.stop()
+					return m, func() tea.Msg { return closeFinderMsg{} }
+				}
+				m.input.Blur()
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return m, cmd
+		}
+		switch msg.String() {
+		case "esc", "q":
+			if m.running {
+				m.stop()
+				return m, nil
+			}
+			return m, func() tea.Msg { return closeFinderMsg{} }
+		case "/":
+			return m, m.input.Focus()
+		case "j", "down":
+			m.cursor = min(m.cursor+1, max(len(m.hits)-1, 0))
+		case "k", "up":
+			m.cursor = max(m.cursor-1, 0)
+		case "pgdown", "ctrl+d":
+			m.cursor = min(m.cursor+m.height/2, max(len(m.hits)-1, 0))
+		case "pgup", "ctrl+u":
+			m.cursor = max(m.cursor-m.height/2, 0)
+		case "enter":
+			if m.cursor < len(m.hits) {
+				hit := m.hits[m.cursor]
+				return m, func() tea.Msg { return openValueHitMsg{hit: hit} }
+			}
+		}
+		return m, nil
+	}
+
+	if m.input.Focused() {
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+var finderErrStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5555"))
+
+func (m FinderModel) status() string {
+	switch {
+	case m.loading && m.queued:
+		return " Loading columns..."
+	case m.value == "":
+		return fmt.Sprintf(" Searches text columns of every table, and numeric columns for numbers (%d rows read per table)", db.DefaultValueSearch.RowLimit)
+	case m.total == 0:
+		return fmt.Sprintf(" No columns can hold %q", m.value)
+	}
+	tables := map[string]bool{}
+	for _, h := range m.hits {
+		tables[h.Schema+"."+h.Table] = true
+	}
+	s := fmt.Sprintf(" %d/%d tables  ·  %d hits in %d tables", m.searched, m.total, len(m.hits), len(tables))
+	switch {
+	case m.truncated:
+		s += fmt.Sprintf("  ·  stopped at %d hits", finderMaxHits)
+	case m.running:
+		s = " Searching..." + s
+	case m.searched < m.total:
+		s += "  ·  cancelled"
+	}
+	if n := len(m.failed); n > 0 {
+		s += fmt.Sprintf("  ·  %d failed", n)
+	}
+	return s
+}
+
+func (m FinderModel) snippet(value string, w int) string {
+	runes := []rune(strings.Join(strings.Fields(value), " "))
+	if len(runes) <= w {
+		return string(runes)
+	}
+	lower := strings.Map(unicode.ToLower, string(runes))
+	start := 0
+	if idx := strings.Index(lower, strings.Map(unicode.ToLower, m.value)); idx >= 0 {
+		start = max(utf8.RuneCountInString(lower[:idx])-w/3, 0)
+	}
+	if start == 0 {
+		return string(runes[:w-1]) + "…"
+	}
+	return "…" + clipLine(string(runes[start:]), w-1)
+}
+
+func (m FinderModel) ViewPanel(w, h int) string {
+	var b strings.Builder
+	b.WriteString(tblHeaderStyle.Render(clipLine(" Find value in "+m.cfg.DBName, w)) + "\n")
+	b.WriteString(" " + m.input.View() + "\n")
+
+	switch {
+	case m.err != nil:
+		b.WriteString(finderErrStyle.Render(clipLine(fmt.Sprintf(" Error: %v", m.err), w)))
+		return b.String()
+	case len(m.failed) > 0:
+		last := m.failed[len(m.failed)-1]
+		b.WriteString(tblTabStyle.Render(clipLine(m.status(), w)) + "\n")
+		b.WriteString(finderErrStyle.Render(clipLine(" "+qualifiedName(last.Schema, last.Table)+": "+strings.Join(strings.Fields(last.Err.Error()), " "), w)) + "\n")
+		h--
+	default:
+		b.WriteString(tblTabStyle.Render(clipLine(m.status(), w)) + "\n")
+	}
+
+	nameW, colW := 0, 0
+	for _, hit := range m.hits {
+		nameW = max(nameW, len([]rune(qualifiedName(hit.Schema, hit.Table))))
+		colW = max(colW, len([]rune(hit.Column)))
+	}
+	nameW, colW = min(nameW, 40), min(colW, 30)
+
+	visible := max(h-3, 1)
+	start := max(m.cursor-visible+1, 0)
+	end := min(start+visible, len(m.hits))
+	for i := start; i < end; i++ {
+		hit := m.hits[i]
+		rows := ""
+		if hit.Rows > 1 {
+			rows = fmt.Sprintf("  (%d rows)", hit.Rows)
+		}
+		prefix := fmt.Sprintf("   %-*s  %-*s  ", nameW, clipLine(qualifiedName(hit.Schema, hit.Table), nameW), colW, clipLine(hit.Column, colW))
+		value := m.snippet(hit.Value, max(w-len([]rune(prefix))-len(rows), 8))
+		if i == m.cursor {
+			b.WriteString(tblSelStyle.Render(clipLine(" ▸"+prefix[2:]+value+rows, w)) + "\n")
+		} else {
+			b.WriteString(tblRowStyle.Render(clipLine(prefix+value, w)) + refCountStyle.Render(rows) + "\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"otto/db"
+)
+
+type followKeyMsg struct {
+	schema  string
+	table   string
+	filters []db.ColumnFilter
+}
+
+type reference struct {
+	fk      db.ForeignKey
+	filters []db.ColumnFilter
+	count   int64
+	err     error
+}
+
+type referencesLoadedMsg struct {
+	session int
+	column  string
+	refs    []reference
+}
+
+type tableNoticeMsg struct {
+	text string
+}
+
+type referenceList struct {
+	column string
+	refs   []reference
+	cursor int
+}
+
+func rowFilters(result *db.QueryResult, row int, names, targets []string) ([]db.ColumnFilter, bool) {
+	filters := make([]db.ColumnFilter, len(names))
+	for i, name := range names {
+		idx := slices.Index(result.Columns, name)
+		if idx < 0 || idx >= len(result.Rows[row]) || result.IsNull(row, idx) {
+			return nil, false
+		}
+		filters[i] = db.ColumnFilter{Column: targets[i], Value: result.RawValue(row, idx)}
+	}
+	return filters, true
+}
+
+func (m TableModel) followKey() tea.Cmd {
+	if m.result == nil || m.cursor >= len(m.result.Rows) || m.colCursor >= len(m.result.Columns) {
+		return nil
+	}
+	d, schema, table, session := m.db, m.schema, m.tableName, m.session
+	result, row := m.result, m.cursor
+	col := result.Columns[m.colCursor]
+	return func() tea.Msg {
+		ctx := context.Background()
+		fks, err := d.ListForeignKeys(ctx, schema, table)
+		if err != nil {
+			return tableNoticeMsg{text: err.Error()}
+		}
+		for _, fk := range fks {
+			if !slices.Contains(fk.Columns, col) {
+				continue
+			}
+			filters, ok := rowFilters(result, row, fk.Columns, fk.RefColumns)
+			if !ok {
+				return tableNoticeMsg{text: fmt.Sprintf("%s is NULL, nothing to follow", col)}
+			}
+			return followKeyMsg{schema: fk.RefSchema, table: fk.RefTable, filters: filters}
+		}
+
+		referencing, err := d.ListReferencingKeys(ctx, schema, table)
+		if err != nil {
+			return tableNoticeMsg{text: err.Error()}
+		}
+		var refs []reference
+		for _, fk := range referencing {
+			if !slices.Contains(fk.RefColumns, col) {
+				continue
+			}
+			filters, ok := rowFilters(result, row, fk.RefColumns, fk.Columns)
+			if !ok {
+				continue
+			}
+			r := reference{fk: fk, filters: filters}
+			r.count, r.err = d.CountRows(ctx, fk.Schema, fk.Table, filters)
+			refs = append(refs, r)
+		}
+		if len(refs) == 0 {
+			return tableNoticeMsg{text: fmt.Sprintf("%s is not part of a foreign key and nothing references it", col)}
+		}
+		return referencesLoadedMsg{session: session, column: col, refs: refs}
+	}
+}
+
+func (m TableModel) updateReferences(msg tea.KeyMsg) (TableModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.refs = nil
+	case "j", "down":
+		m.refs.cursor = min(m.refs.cursor+1, len(m.refs.refs)-1)
+	case "k", "up":
+		m.refs.cursor = max(m.refs.cursor-1, 0)
+	case "enter", "f":
+		r := m.refs.refs[m.refs.cursor]
+		m.refs = nil
+		return m, func() tea.Msg {
+			return followKeyMsg{schema: r.fk.Schema, table: r.fk.Table, filters: r.filters}
+		}
+	}
+	return m, nil
+}
+
+func describeFilters(filters []db.ColumnFilter) string {
+	conds := make([]string, len(filters))
+	for i, f := range filters {
+		conds[i] = f.Column + " = " + f.Value
+	}
+	return strings.Join(conds, " AND ")
+}
+
+var refCountStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#8B949E"))
+
+func (l *referenceList) view(w, h int) string {
+	var b strings.Builder
+	b.WriteString(structSectionStyle.Render(clipLine(fmt.Sprintf(" REFERENCED BY (%s)", l.column), w)) + "\n")
+	visible := max(h, 1)
+	start := max(l.cursor-visible+1, 0)
+	end := min(start+visible, len(l.refs))
+	for i := start; i < end; i++ {
+		r := l.refs[i]
+		count := fmt.Sprintf("%d rows", r.count)
+		if r.err != nil {
+			count = "error: " + r.err.Error()
+		}
+		line := fmt.Sprintf("   %s(%s)  ", qualifiedName(r.fk.Schema, r.fk.Table), strings.Join(r.fk.Columns, ", "))
+		if i == l.cursor {
+			b.WriteString(tblSelStyle.Render(clipLine(" ▸"+line[2:]+count, w)) + "\n")
+		} else {
+			b.WriteString(tblRowStyle.Render(clipLine(line, w)) + refCountStyle.Render(clipLine(count, max(w-len([]rune(line)), 0))) + "\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,186 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"otto/db"
+)
+
+var (
+	gridMatchStyle   = lipgloss.NewStyle().Background(lipgloss.Color("#E3B341")).Foreground(lipgloss.Color("#000000"))
+	gridCurrentStyle = lipgloss.NewStyle().Background(lipgloss.Color("#F0883E")).Foreground(lipgloss.Color("#000000")).Bold(true)
+)
+
+type gridMatch struct {
+	row, col int
+}
+
+// Case-insensitive unless the query contains an upper-case letter.
+type gridSearch struct {
+	input   textinput.Model
+	typing  bool
+	column  int
+	origin  gridMatch
+	matches []gridMatch
+	current int
+}
+
+func newGridSearch(row, col int) *gridSearch {
+	in := textinput.New()
+	in.Prompt = "/"
+	in.CharLimit = 256
+	return &gridSearch{input: in, typing: true, column: -1, origin: gridMatch{row, col}}
+}
+
+func (s *gridSearch) query() string {
+	return s.input.Value()
+}
+
+func (s *gridSearch) fold(text string) []rune {
+	runes := []rune(text)
+	if strings.IndexFunc(s.query(), unicode.IsUpper) >= 0 {
+		return runes
+	}
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+func (s *gridSearch) spans(text string) [][2]int {
+	q := s.fold(s.query())
+	if len(q) == 0 {
+		return nil
+	}
+	t := s.fold(text)
+	var out [][2]int
+	for i := 0; i+len(q) <= len(t); {
+		if string(t[i:i+len(q)]) == string(q) {
+			out = append(out, [2]int{i, i + len(q)})
+			i += len(q)
+		} else {
+			i++
+		}
+	}
+	return out
+}
+
+func (s *gridSearch) find(result *db.QueryResult, vis []int) {
+	row, col := s.origin.row, s.origin.col
+	s.matches, s.current = nil, 0
+	if result == nil || s.query() == "" {
+		return
+	}
+	pos := make(map[int]int, len(vis))
+	for k, c := range vis {
+		pos[c] = k
+	}
+	current := -1
+	for r, values := range result.Rows {
+		for _, c := range vis {
+			if (s.column >= 0 && c != s.column) || c >= len(values) || s.spans(values[c]) == nil {
+				continue
+			}
+			if current < 0 && (r > row || (r == row && (col < 0 || pos[c] >= pos[col]))) {
+				current = len(s.matches)
+			}
+			s.matches = append(s.matches, gridMatch{r, c})
+		}
+	}
+	if current > 0 {
+		s.current = current
+	}
+}
+
+func (s *gridSearch) step(delta int) (gridMatch, bool) {
+	if len(s.matches) == 0 {
+		return gridMatch{}, false
+	}
+	s.current = (s.current + delta + len(s.matches)) % len(s.matches)
+	return s.matches[s.current], true
+}
+
+func (s *gridSearch) at() (gridMatch, bool) {
+	if len(s.matches) == 0 {
+		return gridMatch{}, false
+	}
+	return s.matches[s.current], true
+}
+
+func (s *gridSearch) status(columns []string) string {
+	scope := "all columns"
+	if s.column >= 0 && s.column < len(columns) {
+		scope = columns[s.column]
+	}
+	switch {
+	case s.query() == "":
+		return "  · search " + scope
+	case len(s.matches) == 0:
+		return fmt.Sprintf("  · no match for %q in %s", s.query(), scope)
+	}
+	return fmt.Sprintf("  · match %d/%d in %s", s.current+1, len(s.matches), scope)
+}
+
+func (s *gridSearch) update(msg tea.Msg, column int) (cmd tea.Cmd, changed, done bool) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "enter":
+			s.typing = false
+			s.input.Blur()
+			return nil, false, true
+		case "tab":
+			if s.column >= 0 {
+				s.column = -1
+			} else {
+				s.column = column
+			}
+			return nil, true, false
+		}
+	}
+	before := s.query()
+	s.input, cmd = s.input.Update(msg)
+	return cmd, s.query() != before, false
+}
+
+func (s *gridSearch) highlight(line string, base lipgloss.Style, cells []string, offsets, vis []int, current int) string {
+	runes := []rune(line)
+	marks := make([]int, len(runes))
+	for k, cell := range cells {
+		if offsets[k] < 0 || (s.column >= 0 && vis[k] != s.column) {
+			continue
+		}
+		mark := 1
+		if vis[k] == current {
+			mark = 2
+		}
+		for _, sp := range s.spans(cell) {
+			for i := offsets[k] + sp[0]; i < min(offsets[k]+sp[1], len(marks)); i++ {
+				marks[i] = mark
+			}
+		}
+	}
+	styles := []lipgloss.Style{base, gridMatchStyle, gridCurrentStyle}
+	var b strings.Builder
+	for i := 0; i < len(runes); {
+		j := i
+		for j < len(runes) && marks[j] == marks[i] {
+			j++
+		}
+		b.WriteString(styles[marks[i]].Render(string(runes[i:j])))
+		i = j
+	}
+	return b.String()
+}
+
+func (m TableModel) searchTyping() bool {
+	return m.search != nil && m.search.typing
+}
+
+func (m EditorModel) searchTyping() bool {
+	return m.mode == modeResults && m.search != nil && m.search.typing
+}
@@ -0,0 +1,446 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+
+	"otto/db"
+)
+
+type historySort int
+
+const (
+	sortLastUsed historySort = iota
+	sortName
+)
+
+func (s historySort) String() string {
+	if s == sortName {
+		return "name"
+	}
+	return "last used"
+}
+
+type historyRow struct {
+	group   string
+	index   int
+	count   int
+	project bool
+}
+
+func (r historyRow) isGroup() bool { return r.index < 0 }
+
+var (
+	histGroupStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("#8B949E")).Bold(true)
+	histGroupActiveStyle = lipgloss.NewStyle().Foreground(accentColor).Bold(true)
+	histSearchStyle      = lipgloss.NewStyle().Foreground(textColor)
+	histSortStyle        = lipgloss.NewStyle().Foreground(mutedColor).Italic(true)
+)
+
+func clipRunes(s string, w int) string {
+	if r := []rune(s); len(r) > w && w > 1 {
+		return string(r[:w-1]) + "…"
+	}
+	return s
+}
+
+func historySearchText(cfg db.Config) string {
+	return cfg.Name + " " + db.DisplayName(cfg) + " " + cfg.Group
+}
+
+func (m ConnectModel) orderedIndices(cfgs []db.Config) []int {
+	indices := make([]int, 0, len(cfgs))
+	if m.historyQuery != "" {
+		targets := make([]string, len(cfgs))
+		for i, cfg := range cfgs {
+			targets[i] = strings.ToLower(historySearchText(cfg))
+		}
+		for _, match := range fuzzy.Find(strings.ToLower(m.historyQuery), targets) {
+			indices = append(indices, match.Index)
+		}
+		return indices
+	}
+	for i := range cfgs {
+		indices = append(indices, i)
+	}
+	sort.SliceStable(indices, func(a, b int) bool {
+		ca, cb := cfgs[indices[a]], cfgs[indices[b]]
+		if m.historySort == sortName {
+			return strings.ToLower(db.DisplayName(ca)) < strings.ToLower(db.DisplayName(cb))
+		}
+		return ca.LastUsed.After(cb.LastUsed)
+	})
+	return indices
+}
+
+func (m ConnectModel) projectGroup() string {
+	return "Project · " + filepath.Base(m.project.Dir())
+}
+
+func (m ConnectModel) historyRows() []historyRow {
+	var rows []historyRow
+
+	if m.project != nil && len(m.project.Connections) > 0 {
+		group := m.projectGroup()
+		indices := m.orderedIndices(m.project.Connections)
+		if len(indices) > 0 {
+			rows = append(rows, historyRow{group: group, index: -1, count: len(indices), project: true})
+			if !m.collapsed[group] || m.historyQuery != "" {
+				for _, idx := range indices {
+					rows = append(rows, historyRow{group: group, index: idx, project: true})
+				}
+			}
+		}
+	}
+
+	members := map[string][]int{}
+	var groups []string
+	for _, idx := range m.orderedIndices(m.history) {
+		g := m.history[idx].Group
+		if g == "" {
+			rows = append(rows, historyRow{index: idx})
+			continue
+		}
+		if _, ok := members[g]; !ok {
+			groups = append(groups, g)
+		}
+		members[g] = append(members[g], idx)
+	}
+	if m.historyQuery == "" {
+		sort.Strings(groups)
+	}
+	for _, g := range groups {
+		rows = append(rows, historyRow{group: g, index: -1, count: len(members[g])})
+		if m.collapsed[g] && m.historyQuery == "" {
+			continue
+		}
+		for _, idx := range members[g] {
+			rows = append(rows, historyRow{group: g, index: idx})
+		}
+	}
+	return rows
+}
+
+func (m ConnectModel) rowConfig(row historyRow) db.Config {
+	if row.project {
+		return m.project.Connections[row.index]
+	}
+	return m.history[row.index]
+}
+
+func (m ConnectModel) hasSavedConnections() bool {
+	return len(m.history) > 0 || (m.project != nil && len(m.project.Connections) > 0)
+}
+
+func (m ConnectModel) showHistory() bool {
+	return m.hasSavedConnections() || m.historyErr != nil || m.projectErr != nil
+}
+
+func (m ConnectModel) selectedRow() (historyRow, bool) {
+	rows := m.historyRows()
+	if m.selectedHistory < 0 || m.selectedHistory >= len(rows) {
+		return historyRow{}, false
+	}
+	return rows[m.selectedHistory], true
+}
+
+func (m ConnectModel) shortcutRow(n int) (historyRow, bool) {
+	for _, r := range m.historyRows() {
+		if r.isGroup() {
+			continue
+		}
+		if n == 0 {
+			return r, true
+		}
+		n--
+	}
+	return historyRow{}, false
+}
+
+func (m *ConnectModel) clampHistorySelection() {
+	rows := m.historyRows()
+	if len(rows) == 0 {
+		m.selectedHistory = -1
+		return
+	}
+	if m.selectedHistory >= len(rows) {
+		m.selectedHistory = len(rows) - 1
+	}
+}
+
+func (m ConnectModel) updateHistory(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.historySearch {
+		switch msg.Type {
+		case tea.KeyEsc:
+			m.historySearch = false
+			m.historyQuery = ""
+			m.selectedHistory = 0
+			return m, nil
+		case tea.KeyBackspace:
+			if r := []rune(m.historyQuery); len(r) > 0 {
+				m.historyQuery = string(r[:len(r)-1])
+				m.selectedHistory = 0
+			}
+			return m, nil
+		case tea.KeyRunes, tea.KeySpace:
+			m.historyQuery += string(msg.Runes)
+			m.selectedHistory = 0
+			return m, nil
+		}
+	}
+
+	switch msg.Type {
+	case tea.KeyDown:
+		if m.selectedHistory < len(m.historyRows())-1 {
+			m.selectedHistory++
+		}
+		return m, nil
+	case tea.KeyUp:
+		if m.selectedHistory > 0 {
+			m.selectedHistory--
+		}
+		return m, nil
+	case tea.KeyLeft, tea.KeyRight:
+		if row, ok := m.selectedRow(); ok && row.group != "" {
+			m.collapsed[row.group] = msg.Type == tea.KeyLeft
+			if msg.Type == tea.KeyLeft && !row.isGroup() {
+				for i, r := range m.historyRows() {
+					if r.isGroup() && r.group == row.group {
+						m.selectedHistory = i
+					}
+				}
+			}
+		}
+		return m, nil
+	case tea.KeyEnter:
+		row, ok := m.selectedRow()
+		if !ok {
+			return m, nil
+		}
+		if row.isGroup() {
+			m.collapsed[row.group] = !m.collapsed[row.group]
+			return m, nil
+		}
+		return m, m.connectToRow(row)
+	case tea.KeyTab, tea.KeyEsc:
+		m.historyFocused = false
+		return m, nil
+	}
+
+	if msg.Type != tea.KeyRunes || len(msg.Runes) != 1 {
+		return m, nil
+	}
+	switch r := msg.Runes[0]; {
+	case r == '/':
+		m.historySearch = true
+		m.historyQuery = ""
+		m.selectedHistory = 0
+	case r == 's':
+		if m.historySort == sortLastUsed {
+			m.historySort = sortName
+		} else {
+			m.historySort = sortLastUsed
+		}
+		m.selectedHistory = 0
+	case r == 'd':
+		row, ok := m.selectedRow()
+		if !ok || row.isGroup() || row.project {
+			return m, nil
+		}
+		if err := db.DeleteConnection(m.history[row.index].ID); err != nil {
+			m.historyErr = err
+			return m, nil
+		}
+		m.history, m.historyErr = db.LoadHistory()
+		if !m.showHistory() {
+			m.historyFocused = false
+			m.selectedHistory = -1
+		} else {
+			m.clampHistorySelection()
+		}
+	case r >= '1' && r <= '9':
+		if row, ok := m.shortcutRow(int(r - '1')); ok {
+			return m, m.connectToRow(row)
+		}
+	case r == 'e':
+		row, ok := m.selectedRow()
+		if !ok || row.isGroup() || row.project {
+			return m, nil
+		}
+		cfg := m.history[row.index]
+		m.editingID = cfg.ID
+		m.driver = cfg.Driver
+		if m.driver == "" {
+			m.driver = db.DriverPostgres
+		}
+		m.applyDriverDefaults()
+		m.fillInputs(cfg)
+		m.historyFocused = false
+		m.focused = fieldName
+		for i := range m.inputs {
+			m.inputs[i].Blur()
+		}
+		m.inputs[fieldName].Focus()
+	}
+	return m, nil
+}
+
+func (m ConnectModel) historyListHeight() int {
+	h := m.height
+	if h == 0 {
+		h = 24
+	}
+	h -= 14
+	if h < 5 {
+		h = 5
+	}
+	return h
+}
+
+func (m ConnectModel) renderHistoryRow(row historyRow, active bool, shortcut int) string {
+	if row.isGroup() {
+		arrow := "▾"
+		if m.collapsed[row.group] && m.historyQuery == "" {
+			arrow = "▸"
+		}
+		label := fmt.Sprintf("%s %s (%d)", arrow, row.group, row.count)
+		if active {
+			return fieldArrow.Render("▸") + " " + histGroupActiveStyle.Render(label)
+		}
+		return "  " + histGroupStyle.Render(label)
+	}
+
+	cfg := m.rowConfig(row)
+
+	numText := " "
+	if shortcut >= 0 && shortcut < 9 {
+		numText = fmt.Sprintf("%d", shortcut+1)
+	}
+	var num string
+	if active {
+		num = histNumStyle.Render(numText)
+	} else {
+		num = histNumMutedStyle.Render(numText)
+	}
+
+	var tag string
+	if cfg.Driver == db.DriverMySQL {
+		tag = histTagMYStyle.Render("my")
+	} else {
+		tag = histTagPGStyle.Render("pg")
+	}
+	envTag := renderEnvTag(cfg)
+	if envTag != "" {
+		tag += " " + envTag
+	}
+	if cfg.ReadOnly {
+		tag += " " + histTagROStyle.Render("ro")
+	}
+
+	indent := ""
+	if row.group != "" {
+		indent = "  "
+	}
+
+	name := db.DisplayName(cfg)
+	if maxW := histPanelW - 12 - len(indent) - lipgloss.Width(tag); len([]rune(name)) > maxW && maxW > 1 {
+		name = string([]rune(name)[:maxW-1]) + "…"
+	}
+	var nameStr string
+	if active {
+		nameStr = histNameActiveStyle.Render(name)
+	} else {
+		nameStr = histNameStyle.Render(name)
+	}
+
+	if active {
+		return fieldArrow.Render("▸") + indent + " " + num + "  " + tag + "  " + nameStr
+	}
+	return "  " + indent + num + "  " + tag + "  " + nameStr
+}
+
+func (m ConnectModel) renderHistory() string {
+	title := histTitleStyle.Render("Recent Connections")
+	sortInfo := histSortStyle.Render("sort: " + m.historySort.String())
+	gap := (histPanelW - 4) - lipgloss.Width(title) - lipgloss.Width(sortInfo)
+	if gap < 1 {
+		gap = 1
+	}
+	header := title + strings.Repeat(" ", gap) + sortInfo
+	sep := lipgloss.NewStyle().Foreground(dimColor).Render(strings.Repeat("─", histPanelW-4))
+
+	var notices []string
+	for _, err := range []error{m.historyErr, m.projectErr} {
+		if err != nil {
+			wrapped := lipgloss.NewStyle().Width(histPanelW - 4).Render("✕ " + err.Error())
+			notices = append(notices, errStyle.Render(wrapped))
+		}
+	}
+	if m.historySearch {
+		notices = append(notices, sidebarSearchLabelStyle.Render("/")+histSearchStyle.Render(m.historyQuery+"█"))
+	}
+	search := strings.Join(notices, "\n")
+
+	rows := m.historyRows()
+	listH := m.historyListHeight()
+	start := 0
+	if m.selectedHistory >= listH {
+		start = m.selectedHistory - listH + 1
+	}
+	end := start + listH
+	if end > len(rows) {
+		end = len(rows)
+	}
+
+	shortcuts := make([]int, len(rows))
+	n := 0
+	for i, r := range rows {
+		shortcuts[i] = -1
+		if !r.isGroup() {
+			shortcuts[i] = n
+			n++
+		}
+	}
+
+	var lines []string
+	for i := start; i < end; i++ {
+		lines = append(lines, m.renderHistoryRow(rows[i], i == m.selectedHistory, shortcuts[i]))
+	}
+	if len(rows) == 0 {
+		lines = append(lines, sidebarNoMatchStyle.Render("  no match"))
+	}
+	if end < len(rows) {
+		lines = append(lines, histSortStyle.Render(fmt.Sprintf("  … %d more", len(rows)-end)))
+	}
+
+	list := strings.Join(lines, "\n")
+	var hint string
+	if m.historySearch {
+		hint = histHelpStyle.Render("type to filter · ↑↓ · Enter connect · Esc clear")
+	} else {
+		hint = histHelpStyle.Render("↑↓ select · 1-9/Enter · ←→ fold · / search\ns sort · d delete · e edit · Esc")
+	}
+
+	parts := []string{header, sep}
+	if search != "" {
+		parts = append(parts, search)
+	}
+	parts = append(parts, "", list, "", hint)
+	inner := lipgloss.JoinVertical(lipgloss.Left, parts...)
+
+	borderColor := accentColor
+	if !m.historyFocused {
+		borderColor = dimColor
+	}
+	hStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Padding(1, 2)
+	return hStyle.Width(histPanelW).Render(inner)
+}
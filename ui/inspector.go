@@ -0,0 +1,629 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type valueKind string
+
+const (
+	valueText   valueKind = "text"
+	valueJSON   valueKind = "JSON"
+	valueXML    valueKind = "XML"
+	valueBinary valueKind = "binary"
+	valueUUID   valueKind = "UUID"
+	valueTime   valueKind = "timestamp"
+)
+
+type showCellMsg struct {
+	column string
+	value  string
+}
+
+type closeInspectorMsg struct{}
+
+const (
+	inspPlain = iota
+	inspKey
+	inspString
+	inspNumber
+	inspKeyword
+	inspPunct
+	inspMuted
+	inspMatch
+	inspCurrent
+)
+
+var inspStyles = []lipgloss.Style{
+	inspPlain:   tblRowStyle,
+	inspKey:     lipgloss.NewStyle().Foreground(lipgloss.Color("#79C0FF")),
+	inspString:  lipgloss.NewStyle().Foreground(lipgloss.Color("#A5D6A7")),
+	inspNumber:  lipgloss.NewStyle().Foreground(lipgloss.Color("#FFA657")),
+	inspKeyword: lipgloss.NewStyle().Foreground(lipgloss.Color("#D2A8FF")),
+	inspPunct:   lipgloss.NewStyle().Foreground(lipgloss.Color("#8B949E")),
+	inspMuted:   lipgloss.NewStyle().Foreground(lipgloss.Color("#555555")),
+	inspMatch:   lipgloss.NewStyle().Background(lipgloss.Color("#E3B341")).Foreground(lipgloss.Color("#000000")),
+	inspCurrent: tblSelStyle,
+}
+
+type span struct {
+	text  string
+	class int
+}
+
+type styledLine []span
+
+func (l styledLine) plain() string {
+	var b strings.Builder
+	for _, s := range l {
+		b.WriteString(s.text)
+	}
+	return b.String()
+}
+
+func plainLines(lines []string, class int) []styledLine {
+	out := make([]styledLine, len(lines))
+	for i, l := range lines {
+		out[i] = styledLine{{l, class}}
+	}
+	return out
+}
+
+var (
+	uuidRe     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	pgByteaRe  = regexp.MustCompile(`^\\x([0-9a-fA-F]{2})*$`)
+	mysqlHexRe = regexp.MustCompile(`^0x([0-9A-F]{2})+$`)
+	timeLayout = []string{
+		time.RFC3339Nano,
+		"2006-01-02 15:04:05.999999999Z07:00",
+		"2006-01-02 15:04:05.999999999Z07",
+		"2006-01-02 15:04:05.999999999",
+		"2006-01-02T15:04:05.999999999",
+		"2006-01-02",
+	}
+)
+
+func detectValue(v string) (valueKind, []byte) {
+	trimmed := strings.TrimSpace(v)
+	switch {
+	case pgByteaRe.MatchString(v), mysqlHexRe.MatchString(v):
+		b, _ := hex.DecodeString(v[2:])
+		return valueBinary, b
+	case !utf8.ValidString(v) || strings.ContainsRune(v, 0):
+		return valueBinary, []byte(v)
+	case uuidRe.MatchString(trimmed):
+		return valueUUID, nil
+	case (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)):
+		return valueJSON, nil
+	case strings.HasPrefix(trimmed, "<") && xmlTokens(trimmed) != nil:
+		return valueXML, nil
+	}
+	if _, ok := parseTimestamp(trimmed); ok {
+		return valueTime, nil
+	}
+	return valueText, nil
+}
+
+func parseTimestamp(s string) (time.Time, bool) {
+	if len(s) < 10 || s[4] != '-' {
+		return time.Time{}, false
+	}
+	for _, layout := range timeLayout {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func jsonLines(v string) []styledLine {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(strings.TrimSpace(v)), "", "  "); err != nil {
+		return plainLines(strings.Split(v, "\n"), inspPlain)
+	}
+	var out []styledLine
+	for _, line := range strings.Split(buf.String(), "\n") {
+		var l styledLine
+		for i := 0; i < len(line); {
+			c := line[i]
+			j := i + 1
+			class := inspPunct
+			switch {
+			case c == '"':
+				for j < len(line) && line[j] != '"' {
+					if line[j] == '\\' {
+						j++
+					}
+					j++
+				}
+				j = min(j+1, len(line))
+				class = inspString
+				if strings.HasPrefix(strings.TrimLeft(line[j:], " "), ":") {
+					class = inspKey
+				}
+			case c == '-' || (c >= '0' && c <= '9'):
+				for j < len(line) && strings.IndexByte("0123456789.eE+-", line[j]) >= 0 {
+					j++
+				}
+				class = inspNumber
+			case c >= 'a' && c <= 'z':
+				for j < len(line) && line[j] >= 'a' && line[j] <= 'z' {
+					j++
+				}
+				class = inspKeyword
+			case c == ' ':
+				for j < len(line) && line[j] == ' ' {
+					j++
+				}
+				class = inspPlain
+			}
+			l = append(l, span{line[i:j], class})
+			i = j
+		}
+		out = append(out, l)
+	}
+	return out
+}
+
+func xmlTokens(v string) []xml.Token {
+	d := xml.NewDecoder(strings.NewReader(v))
+	d.Strict = false
+	var toks []xml.Token
+	elements := 0
+	for {
+		t, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil
+		}
+		if _, ok := t.(xml.StartElement); ok {
+			elements++
+		}
+		toks = append(toks, xml.CopyToken(t))
+	}
+	if elements == 0 {
+		return nil
+	}
+	return toks
+}
+
+func xmlName(n xml.Name) string {
+	if n.Space != "" {
+		return n.Space + ":" + n.Local
+	}
+	return n.Local
+}
+
+func xmlStartTag(e xml.StartElement, selfClose bool) styledLine {
+	l := styledLine{{"<", inspPunct}, {xmlName(e.Name), inspKey}}
+	for _, a := range e.Attr {
+		l = append(l, span{" " + xmlName(a.Name), inspNumber}, span{"=", inspPunct}, span{`"` + a.Value + `"`, inspString})
+	}
+	if selfClose {
+		return append(l, span{"/>", inspPunct})
+	}
+	return append(l, span{">", inspPunct})
+}
+
+func xmlEndTag(e xml.EndElement) styledLine {
+	return styledLine{{"</", inspPunct}, {xmlName(e.Name), inspKey}, {">", inspPunct}}
+}
+
+func xmlLines(v string) []styledLine {
+	toks := xmlTokens(strings.TrimSpace(v))
+	var out []styledLine
+	depth := 0
+	indent := func() span { return span{strings.Repeat("  ", depth), inspPlain} }
+	for i := 0; i < len(toks); i++ {
+		switch t := toks[i].(type) {
+		case xml.StartElement:
+			if i+1 < len(toks) {
+				if _, ok := toks[i+1].(xml.EndElement); ok {
+					out = append(out, append(styledLine{indent()}, xmlStartTag(t, true)...))
+					i++
+					continue
+				}
+			}
+			if i+2 < len(toks) {
+				cd, isText := toks[i+1].(xml.CharData)
+				end, isEnd := toks[i+2].(xml.EndElement)
+				if isText && isEnd && !strings.Contains(string(cd), "\n") {
+					l := append(styledLine{indent()}, xmlStartTag(t, false)...)
+					l = append(l, span{string(cd), inspPlain})
+					out = append(out, append(l, xmlEndTag(end)...))
+					i += 2
+					continue
+				}
+			}
+			out = append(out, append(styledLine{indent()}, xmlStartTag(t, false)...))
+			depth++
+		case xml.EndElement:
+			depth = max(depth-1, 0)
+			out = append(out, append(styledLine{indent()}, xmlEndTag(t)...))
+		case xml.CharData:
+			for _, line := range strings.Split(strings.TrimSpace(string(t)), "\n") {
+				if line = strings.TrimSpace(line); line != "" {
+					out = append(out, styledLine{indent(), {line, inspPlain}})
+				}
+			}
+		case xml.Comment:
+			out = append(out, styledLine{indent(), {"<!--" + string(t) + "-->", inspMuted}})
+		case xml.ProcInst:
+			out = append(out, styledLine{indent(), {"<?" + t.Target + " " + string(t.Inst) + "?>", inspMuted}})
+		case xml.Directive:
+			out = append(out, styledLine{indent(), {"<!" + string(t) + ">", inspMuted}})
+		}
+	}
+	return out
+}
+
+const hexDumpLimit = 64 << 10
+
+func hexLines(b []byte) []styledLine {
+	var out []styledLine
+	data := b[:min(len(b), hexDumpLimit)]
+	for off := 0; off < len(data); off += 16 {
+		chunk := data[off:min(off+16, len(data))]
+		var hexPart, ascii strings.Builder
+		for i := range 16 {
+			if i == 8 {
+				hexPart.WriteByte(' ')
+			}
+			if i < len(chunk) {
+				fmt.Fprintf(&hexPart, "%02x ", chunk[i])
+			} else {
+				hexPart.WriteString("   ")
+			}
+		}
+		for _, c := range chunk {
+			if c >= 0x20 && c < 0x7f {
+				ascii.WriteByte(c)
+			} else {
+				ascii.WriteByte('.')
+			}
+		}
+		out = append(out, styledLine{
+			{fmt.Sprintf("%08x  ", off), inspMuted},
+			{hexPart.String(), inspPlain},
+			{" |" + ascii.String() + "|", inspString},
+		})
+	}
+	if len(b) > len(data) {
+		out = append(out, styledLine{{fmt.Sprintf("… %d more bytes not shown", len(b)-len(data)), inspMuted}})
+	}
+	return out
+}
+
+func field(label, value string) styledLine {
+	return styledLine{{padRight(label, 10), inspPunct}, {value, inspPlain}}
+}
+
+func uuidLines(v string) []styledLine {
+	v = strings.ToLower(strings.TrimSpace(v))
+	raw, _ := hex.DecodeString(strings.ReplaceAll(v, "-", ""))
+	version := int(raw[6] >> 4)
+	kinds := map[int]string{1: "time-based", 3: "name-based, MD5", 4: "random", 5: "name-based, SHA-1", 6: "reordered time", 7: "Unix time-ordered"}
+	variant := "RFC 9562"
+	switch {
+	case raw[8]&0x80 == 0:
+		variant = "NCS (reserved)"
+	case raw[8]&0xc0 == 0xc0:
+		variant = "Microsoft (reserved)"
+	}
+	out := []styledLine{
+		field("uuid", v),
+		field("version", fmt.Sprintf("%d (%s)", version, kinds[version])),
+		field("variant", variant),
+	}
+	var ts time.Time
+	switch version {
+	case 7:
+		ms := int64(raw[0])<<40 | int64(raw[1])<<32 | int64(raw[2])<<24 | int64(raw[3])<<16 | int64(raw[4])<<8 | int64(raw[5])
+		ts = time.UnixMilli(ms)
+	case 1, 6:
+		var ticks int64
+		if version == 1 {
+			ticks = int64(raw[6]&0x0f)<<56 | int64(raw[7])<<48 | int64(raw[4])<<40 | int64(raw[5])<<32 |
+				int64(raw[0])<<24 | int64(raw[1])<<16 | int64(raw[2])<<8 | int64(raw[3])
+		} else {
+			ticks = int64(raw[0])<<52 | int64(raw[1])<<44 | int64(raw[2])<<36 | int64(raw[3])<<28 |
+				int64(raw[4])<<20 | int64(raw[5])<<12 | int64(raw[6]&0x0f)<<8 | int64(raw[7])
+		}
+		// 100ns intervals since 1582-10-15, shifted to the Unix epoch.
+		unix := ticks - 0x01B21DD213814000
+		ts = time.Unix(unix/1e7, unix%1e7*100)
+	}
+	if !ts.IsZero() {
+		out = append(out, field("created", ts.UTC().Format("2006-01-02 15:04:05.000 MST")+"  ("+relativeTime(ts)+")"))
+	}
+	return out
+}
+
+func relativeTime(t time.Time) string {
+	d := time.Since(t)
+	suffix := "ago"
+	if d < 0 {
+		d, suffix = -d, "from now"
+	}
+	var s string
+	switch {
+	case d < time.Minute:
+		s = fmt.Sprintf("%d seconds", int(d.Seconds()))
+	case d < time.Hour:
+		s = fmt.Sprintf("%d minutes", int(d.Minutes()))
+	case d < 48*time.Hour:
+		s = fmt.Sprintf("%d hours", int(d.Hours()))
+	case d < 60*24*time.Hour:
+		s = fmt.Sprintf("%d days", int(d.Hours()/24))
+	case d < 2*365*24*time.Hour:
+		s = fmt.Sprintf("%d months", int(d.Hours()/24/30))
+	default:
+		s = fmt.Sprintf("%d years", int(d.Hours()/24/365))
+	}
+	return s + " " + suffix
+}
+
+func timeLines(v string) []styledLine {
+	t, _ := parseTimestamp(strings.TrimSpace(v))
+	return []styledLine{
+		field("value", strings.TrimSpace(v)),
+		field("UTC", t.UTC().Format("2006-01-02 15:04:05.999999 MST")),
+		field("local", t.Local().Format("2006-01-02 15:04:05.999999 MST (-07:00)")),
+		field("unix", fmt.Sprint(t.Unix())),
+		field("weekday", t.Weekday().String()),
+		field("relative", relativeTime(t)),
+	}
+}
+
+type inspMatchPos struct {
+	line, col int
+}
+
+type CellInspector struct {
+	column    string
+	value     string
+	kind      valueKind
+	binary    []byte
+	lines     []styledLine
+	raw       bool
+	scroll    int
+	scrollX   int
+	width     int
+	height    int
+	searching bool
+	search    textinput.Model
+	query     string
+	matches   []inspMatchPos
+	match     int
+}
+
+func NewCellInspector(column, value string, width, height int) CellInspector {
+	in := textinput.New()
+	in.Prompt = "/"
+	in.CharLimit = 256
+	m := CellInspector{column: column, value: value, width: width, height: height, search: in}
+	m.kind, m.binary = detectValue(value)
+	m.build()
+	return m
+}
+
+func (m *CellInspector) build() {
+	if m.raw {
+		m.lines = plainLines(wrapRunes(m.value, max(m.width-1, 10)), inspPlain)
+	} else {
+		switch m.kind {
+		case valueJSON:
+			m.lines = jsonLines(m.value)
+		case valueXML:
+			m.lines = xmlLines(m.value)
+		case valueBinary:
+			m.lines = hexLines(m.binary)
+		case valueUUID:
+			m.lines = uuidLines(m.value)
+		case valueTime:
+			m.lines = timeLines(m.value)
+		default:
+			m.lines = plainLines(wrapRunes(m.value, max(m.width-1, 10)), inspPlain)
+		}
+	}
+	m.findMatches()
+}
+
+func (m *CellInspector) resize(w, h int) {
+	if w != m.width {
+		m.width = w
+		m.build()
+	}
+	m.height = h
+}
+
+func (m *CellInspector) findMatches() {
+	m.matches, m.match = nil, 0
+	if m.query == "" {
+		return
+	}
+	q := []rune(strings.ToLower(m.query))
+	for i, l := range m.lines {
+		text := []rune(strings.ToLower(l.plain()))
+		for c := 0; c+len(q) <= len(text); {
+			if string(text[c:c+len(q)]) == string(q) {
+				m.matches = append(m.matches, inspMatchPos{i, c})
+				c += len(q)
+			} else {
+				c++
+			}
+		}
+	}
+}
+
+func (m CellInspector) visibleLines() int {
+	return max(m.height-2, 1)
+}
+
+func (m *CellInspector) jumpToMatch() {
+	if len(m.matches) == 0 {
+		return
+	}
+	p := m.matches[m.match]
+	if p.line < m.scroll || p.line >= m.scroll+m.visibleLines() {
+		m.scroll = max(p.line-m.visibleLines()/2, 0)
+	}
+	if p.col < m.scrollX || p.col >= m.scrollX+m.width-2 {
+		m.scrollX = max(p.col-m.width/2, 0)
+	}
+}
+
+func (m CellInspector) Update(msg tea.Msg) (CellInspector, tea.Cmd) {
+	if m.searching {
+		if key, ok := msg.(tea.KeyMsg); ok {
+			switch key.String() {
+			case "esc":
+				m.searching = false
+				m.search.Blur()
+				return m, nil
+			case "enter":
+				m.searching = false
+				m.search.Blur()
+				m.query = m.search.Value()
+				m.findMatches()
+				m.jumpToMatch()
+				return m, nil
+			}
+		}
+		var cmd tea.Cmd
+		m.search, cmd = m.search.Update(msg)
+		return m, cmd
+	}
+
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	maxScroll := max(len(m.lines)-m.visibleLines(), 0)
+	switch key.String() {
+	case "esc", "q":
+		if m.query != "" {
+			m.query = ""
+			m.findMatches()
+			return m, nil
+		}
+		return m, func() tea.Msg { return closeInspectorMsg{} }
+	case "/":
+		m.searching = true
+		m.search.SetValue(m.query)
+		m.search.CursorEnd()
+		return m, m.search.Focus()
+	case "n":
+		if len(m.matches) > 0 {
+			m.match = (m.match + 1) % len(m.matches)
+			m.jumpToMatch()
+		}
+	case "N":
+		if len(m.matches) > 0 {
+			m.match = (m.match - 1 + len(m.matches)) % len(m.matches)
+			m.jumpToMatch()
+		}
+	case "m":
+		m.raw = !m.raw
+		m.scroll, m.scrollX = 0, 0
+		m.build()
+	case "j", "down":
+		m.scroll = min(m.scroll+1, maxScroll)
+	case "k", "up":
+		m.scroll = max(m.scroll-1, 0)
+	case "pgdown", "ctrl+d":
+		m.scroll = min(m.scroll+m.height/2, maxScroll)
+	case "pgup", "ctrl+u":
+		m.scroll = max(m.scroll-m.height/2, 0)
+	case "g", "home":
+		m.scroll = 0
+	case "G", "end":
+		m.scroll = maxScroll
+	case "l", "right":
+		m.scrollX += 8
+	case "h", "left":
+		m.scrollX = max(m.scrollX-8, 0)
+	}
+	return m, nil
+}
+
+func (m CellInspector) renderLine(i int, l styledLine, w int) string {
+	var runes []rune
+	var classes []int
+	for _, s := range l {
+		for _, r := range s.text {
+			runes = append(runes, r)
+			classes = append(classes, s.class)
+		}
+	}
+	for k, p := range m.matches {
+		if p.line != i {
+			continue
+		}
+		class := inspMatch
+		if k == m.match {
+			class = inspCurrent
+		}
+		for c := p.col; c < min(p.col+len([]rune(m.query)), len(classes)); c++ {
+			classes[c] = class
+		}
+	}
+	end := min(m.scrollX+w, len(runes))
+	var b strings.Builder
+	for x := m.scrollX; x < end; {
+		run := x
+		for run < end && classes[run] == classes[x] {
+			run++
+		}
+		b.WriteString(inspStyles[classes[x]].Render(string(runes[x:run])))
+		x = run
+	}
+	return b.String()
+}
+
+func (m CellInspector) ViewPanel(w, h int) string {
+	var b strings.Builder
+	size := len(m.value)
+	if m.binary != nil {
+		size = len(m.binary)
+	}
+	info := fmt.Sprintf("  %s · %s · %d lines", m.kind, formatBytes(int64(size)), len(m.lines))
+	if m.raw {
+		info += " · raw"
+	}
+	if m.query != "" {
+		if len(m.matches) == 0 {
+			info += fmt.Sprintf(" · no match for %q", m.query)
+		} else {
+			info += fmt.Sprintf(" · match %d/%d", m.match+1, len(m.matches))
+		}
+	}
+	title := tblHeaderStyle.Render(" " + m.column)
+	b.WriteString(title + tblTabStyle.Render(clipLine(info, max(w-lipgloss.Width(title), 0))) + "\n")
+	if m.searching {
+		b.WriteString(" " + m.search.View())
+	}
+	b.WriteString("\n")
+
+	visible := m.visibleLines()
+	end := min(m.scroll+visible, len(m.lines))
+	for i := m.scroll; i < end; i++ {
+		b.WriteString(" " + m.renderLine(i, m.lines[i], w-1) + "\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
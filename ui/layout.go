@@ -4,13 +4,14 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"otto/db"
 )
 
-const sidebarW = 26
+const sidebarW = 30
 
 type contentPane int
 
@@ -18,6 +19,10 @@ const (
 	paneWelcome contentPane = iota
 	paneTable
 	paneEditor
+	paneDDL
+	paneERD
+	paneInspector
+	paneFinder
 )
 
 type panelFocus int
@@ -29,19 +34,63 @@ const (
 
 type GoBackToConnectMsg struct{}
 
+type connHealth int
+
+const (
+	healthOK connHealth = iota
+	healthReconnecting
+	healthDown
+)
+
+const healthInterval = 15 * time.Second
+
+type healthTickMsg struct {
+	session int
+}
+
+type healthResultMsg struct {
+	session int
+	err     error
+}
+
+type reconnectMsg struct {
+	session int
+	event   db.ReconnectEvent
+}
+
 type MainModel struct {
 	db      db.DB
 	cfg     db.Config
 	sidebar SidebarModel
 	table   TableModel
 	editor  EditorModel
+	ddl     DDLModel
+	erd     ERDModel
+	cell    CellInspector
+	finder  FinderModel
 	content contentPane
 	focus   panelFocus
 	width   int
 	height  int
+
+	notice       string
+	picker       *databasePicker
+	ddlReturn    contentPane
+	erdReturn    contentPane
+	cellReturn   contentPane
+	finderReturn contentPane
+	tableReturn  contentPane
+	tableStack   []TableModel
+
+	health        connHealth
+	healthErr     error
+	healthSession int
+	sessions      int
 }
 
-func NewMainModel(d db.DB, cfg db.Config, width, height int) MainModel {
+// sessions continues the counter of the previous MainModel, so that
+// messages still in flight from it are never taken for this one's.
+func NewMainModel(d db.DB, cfg db.Config, width, height, sessions int) MainModel {
 	if width == 0 {
 		width = 80
 	}
@@ -50,13 +99,15 @@ func NewMainModel(d db.DB, cfg db.Config, width, height int) MainModel {
 	}
 	_, ch := contentDims(width, height)
 	return MainModel{
-		db:      d,
-		cfg:     cfg,
-		sidebar: NewSidebarModel(d, cfg, sidebarW, ch),
-		content: paneWelcome,
-		focus:   focusSidebar,
-		width:   width,
-		height:  height,
+		db:            d,
+		cfg:           cfg,
+		sidebar:       NewSidebarModel(d, cfg, sidebarW, ch, sessions+1),
+		content:       paneWelcome,
+		focus:         focusSidebar,
+		width:         width,
+		height:        height,
+		healthSession: sessions + 1,
+		sessions:      sessions + 1,
 	}
 }
 
@@ -77,7 +128,35 @@ func (m MainModel) dims() (int, int) {
 }
 
 func (m MainModel) Init() tea.Cmd {
-	return m.sidebar.Init()
+	return tea.Batch(m.sidebar.Init(), m.scheduleHealthCheck(), m.watchReconnects())
+}
+
+func (m MainModel) watchReconnects() tea.Cmd {
+	events := db.ReconnectEvents(m.db)
+	if events == nil {
+		return nil
+	}
+	session := m.healthSession
+	return func() tea.Msg {
+		ev, ok := <-events
+		if !ok {
+			return nil
+		}
+		return reconnectMsg{session: session, event: ev}
+	}
+}
+
+func (m MainModel) scheduleHealthCheck() tea.Cmd {
+	session := m.healthSession
+	return tea.Tick(healthInterval, func(time.Time) tea.Msg {
+		return healthTickMsg{session: session}
+	})
+}
+
+func (m MainModel) checkHealth() tea.Msg {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	return healthResultMsg{session: m.healthSession, err: m.db.Ping(ctx)}
 }
 
 func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -92,22 +171,211 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.table.height = ch
 		m.editor.width = cw
 		m.editor.height = ch
+		m.ddl.width = cw
+		m.ddl.height = ch
+		m.erd.width = cw
+		m.erd.height = ch
+		m.cell.resize(cw, ch)
+		m.finder.width = cw
+		m.finder.height = ch
 		if m.content == paneEditor {
 			m.editor.textarea.SetWidth(cw - 2)
 		}
 		return m, nil
 
-	case sidebarTablesMsg:
+	case sidebarSchemasMsg, sidebarObjectsMsg:
 		var cmd tea.Cmd
 		m.sidebar, cmd = m.sidebar.Update(msg)
 		return m, cmd
 
+	case healthTickMsg:
+		if msg.session != m.healthSession {
+			return m, nil
+		}
+		if m.health == healthDown {
+			m.health = healthReconnecting
+		}
+		return m, m.checkHealth
+
+	case healthResultMsg:
+		if msg.session != m.healthSession {
+			return m, nil
+		}
+		wasDown := m.health != healthOK
+		m.healthErr = msg.err
+		if msg.err != nil {
+			m.health = healthDown
+			return m, m.scheduleHealthCheck()
+		}
+		m.health = healthOK
+		if wasDown {
+			return m, tea.Batch(m.sidebar.Init(), m.scheduleHealthCheck())
+		}
+		return m, m.scheduleHealthCheck()
+
+	case reconnectMsg:
+		if msg.session != m.healthSession {
+			return m, nil
+		}
+		switch {
+		case msg.event.Reconnecting:
+			m.health = healthReconnecting
+		case msg.event.Err != nil:
+			m.health, m.healthErr = healthDown, msg.event.Err
+		default:
+			m.health, m.healthErr = healthOK, nil
+		}
+		return m, m.watchReconnects()
+
+	case databasesLoadedMsg:
+		if m.picker == nil {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.picker.loading = false
+			m.picker.err = msg.err
+			return m, nil
+		}
+		m.picker.setNames(msg.names)
+		return m, nil
+
+	case databaseSwitchedMsg:
+		if msg.err != nil {
+			if m.picker != nil {
+				m.picker.switching = ""
+				m.picker.err = msg.err
+			}
+			return m, nil
+		}
+		m.picker = nil
+		m.cfg.DBName = msg.name
+		m.finder.stop()
+		m.finder = FinderModel{}
+		m.table, m.tableStack = TableModel{}, nil
+		m.ddl, m.erd, m.cell = DDLModel{}, ERDModel{}, CellInspector{}
+		_, ch := m.dims()
+		m.sessions++
+		m.sidebar = NewSidebarModel(m.db, m.cfg, sidebarW, ch, m.sessions)
+		m.sidebar.focused = m.focus == focusSidebar
+		cmds := []tea.Cmd{m.sidebar.Init()}
+		m.editor.cfg = m.cfg
+		if m.content == paneEditor {
+			cmds = append(cmds, m.editor.loadTables, m.editor.loadColumns)
+		} else if m.content != paneWelcome {
+			m.content = paneWelcome
+			m.focus = focusSidebar
+			m.sidebar.focused = true
+		}
+		return m, tea.Batch(cmds...)
+
+	case showDDLMsg:
+		return m.openDDL(msg.object)
+
+	case closeDDLMsg:
+		m.content = m.ddlReturn
+		if m.content == paneWelcome {
+			m.focus = focusSidebar
+			m.sidebar.focused = true
+		}
+		return m, nil
+
+	case showERDMsg:
+		if m.content != paneERD {
+			m.erdReturn = m.content
+		}
+		cw, ch := m.dims()
+		m.erd = NewERDModel(m.db, msg.schema, msg.table, cw, ch)
+		m.content = paneERD
+		m.focus = focusContent
+		m.sidebar.focused = false
+		return m, m.erd.Init()
+
+	case closeERDMsg:
+		m.content = m.erdReturn
+		if m.content == paneWelcome {
+			m.focus = focusSidebar
+			m.sidebar.focused = true
+		}
+		return m, nil
+
+	case showCellMsg:
+		if m.content != paneInspector {
+			m.cellReturn = m.content
+		}
+		cw, ch := m.dims()
+		m.cell = NewCellInspector(msg.column, msg.value, cw, ch)
+		m.content = paneInspector
+		m.focus = focusContent
+		m.sidebar.focused = false
+		return m, nil
+
+	case closeInspectorMsg:
+		m.content = m.cellReturn
+		return m, nil
+
+	case openInEditorMsg:
+		cw, ch := m.dims()
+		m.editor = NewEditorModel(m.db, m.cfg, cw, ch)
+		m.editor.textarea.SetValue(msg.query)
+		m.content = paneEditor
+		m.focus = focusContent
+		m.sidebar.focused = false
+		return m, m.editor.Init()
+
+	case closeFinderMsg:
+		m.content = m.finderReturn
+		if m.content == paneWelcome {
+			m.focus = focusSidebar
+			m.sidebar.focused = true
+		}
+		return m, nil
+
+	case openValueHitMsg:
+		cw, ch := m.dims()
+		m.sessions++
+		m.table = NewTableModel(m.db, msg.hit.Schema, msg.hit.Table, cw, ch, m.sessions)
+		m.table.layoutKey = db.LayoutKey(m.cfg, msg.hit.Schema, msg.hit.Table)
+		m.table.filters = []db.ColumnFilter{{Column: msg.hit.Column, Value: msg.hit.Value}}
+		m.tableStack = nil
+		m.tableReturn = paneFinder
+		m.finderReturn = paneWelcome
+		m.content = paneTable
+		return m, m.table.Init()
+
+	case followKeyMsg:
+		cw, ch := m.dims()
+		m.tableStack = append(m.tableStack, m.table)
+		m.sessions++
+		m.table = NewTableModel(m.db, msg.schema, msg.table, cw, ch, m.sessions)
+		m.table.layoutKey = db.LayoutKey(m.cfg, msg.schema, msg.table)
+		m.table.filters = msg.filters
+		return m, m.table.Init()
+
+	case tableNoticeMsg:
+		m.notice = msg.text
+		return m, nil
+
 	case GoBackMsg:
+		if n := len(m.tableStack); n > 0 && m.content == paneTable {
+			m.table = m.tableStack[n-1]
+			m.tableStack = m.tableStack[:n-1]
+			m.table.width, m.table.height = m.dims()
+			return m, nil
+		}
+		if m.content == paneTable && m.tableReturn == paneFinder {
+			m.tableReturn = paneWelcome
+			m.content = paneFinder
+			return m, nil
+		}
 		m.focus = focusSidebar
 		m.sidebar.focused = true
 		return m, nil
 
 	case tea.KeyMsg:
+		m.notice = ""
+		if m.picker != nil {
+			return m.updatePicker(msg)
+		}
 		switch msg.String() {
 		case "esc":
 			if m.focus == focusSidebar {
@@ -116,6 +384,7 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.sidebar, cmd = m.sidebar.Update(msg)
 					return m, cmd
 				}
+				m.finder.stop()
 				if m.db != nil {
 					m.db.Close(context.Background())
 				}
@@ -125,8 +394,13 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.sidebar.searching {
 				break
 			}
-			if m.focus == focusContent && m.content == paneEditor && m.editor.CompletionActive() {
+			if m.focus == focusContent && (m.content == paneEditor && (m.editor.CompletionActive() || m.editor.searchTyping()) ||
+				m.content == paneTable && m.table.searchTyping()) {
 				var cmd tea.Cmd
+				if m.content == paneTable {
+					m.table, cmd = m.table.Update(msg)
+					return m, cmd
+				}
 				m.editor, cmd = m.editor.Update(msg)
 				return m, cmd
 			}
@@ -143,21 +417,63 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter":
 			if m.focus == focusSidebar {
 				m.sidebar.searching = false
-				t := m.sidebar.SelectedTable()
-				if t != nil {
+				if o := m.sidebar.SelectedObject(); o != nil && o.Kind.HasRows() {
 					cw, ch := m.dims()
-					m.table = NewTableModel(m.db, t.Schema, t.Name, cw, ch)
+					m.sessions++
+					m.table = NewTableModel(m.db, o.Schema, o.Name, cw, ch, m.sessions)
+					m.table.layoutKey = db.LayoutKey(m.cfg, o.Schema, o.Name)
+					m.table.kind = o.Kind
+					m.tableStack = nil
+					m.tableReturn = paneWelcome
 					m.content = paneTable
 					m.focus = focusContent
 					m.sidebar.focused = false
 					return m, m.table.Init()
 				}
+			}
+		case "c":
+			if m.focus == focusSidebar && !m.sidebar.searching {
+				if o := m.sidebar.SelectedObject(); o != nil {
+					return m.openDDL(*o)
+				}
 				return m, nil
 			}
+		case "e":
+			if m.focus == focusSidebar && !m.sidebar.searching {
+				msg := showERDMsg{schema: m.sidebar.SelectedSchema()}
+				if o := m.sidebar.SelectedObject(); o != nil && o.Kind == db.KindTable {
+					msg.table = o.Name
+				}
+				if msg.schema == "" {
+					return m, nil
+				}
+				return m, func() tea.Msg { return msg }
+			}
+		case "b":
+			if m.focus == focusSidebar && !m.sidebar.searching {
+				m.picker = newDatabasePicker(m.cfg.DBName)
+				return m, loadDatabases(m.db)
+			}
+		case "f":
+			if m.focus == focusSidebar && !m.sidebar.searching {
+				if m.content != paneFinder {
+					m.finderReturn = m.content
+				}
+				m.content = paneFinder
+				m.focus = focusContent
+				m.sidebar.focused = false
+				if m.finder.db == nil {
+					cw, ch := m.dims()
+					m.sessions++
+					m.finder = NewFinderModel(m.db, m.cfg, cw, ch, m.sessions)
+					return m, m.finder.Init()
+				}
+				return m, m.finder.input.Focus()
+			}
 		case "s":
 			if m.focus == focusSidebar && !m.sidebar.searching {
 				cw, ch := m.dims()
-				m.editor = NewEditorModel(m.db, cw, ch)
+				m.editor = NewEditorModel(m.db, m.cfg, cw, ch)
 				m.content = paneEditor
 				m.focus = focusContent
 				m.sidebar.focused = false
@@ -181,6 +497,22 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			var cmd tea.Cmd
 			m.editor, cmd = m.editor.Update(msg)
 			return m, cmd
+		case paneDDL:
+			var cmd tea.Cmd
+			m.ddl, cmd = m.ddl.Update(msg)
+			return m, cmd
+		case paneERD:
+			var cmd tea.Cmd
+			m.erd, cmd = m.erd.Update(msg)
+			return m, cmd
+		case paneInspector:
+			var cmd tea.Cmd
+			m.cell, cmd = m.cell.Update(msg)
+			return m, cmd
+		case paneFinder:
+			var cmd tea.Cmd
+			m.finder, cmd = m.finder.Update(msg)
+			return m, cmd
 		}
 
 	default:
@@ -208,6 +540,38 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		if m.content == paneDDL {
+			var dCmd tea.Cmd
+			m.ddl, dCmd = m.ddl.Update(msg)
+			if dCmd != nil {
+				cmds = append(cmds, dCmd)
+			}
+		}
+
+		if m.content == paneInspector {
+			var iCmd tea.Cmd
+			m.cell, iCmd = m.cell.Update(msg)
+			if iCmd != nil {
+				cmds = append(cmds, iCmd)
+			}
+		}
+
+		if m.content == paneFinder || m.finder.running {
+			var fCmd tea.Cmd
+			m.finder, fCmd = m.finder.Update(msg)
+			if fCmd != nil {
+				cmds = append(cmds, fCmd)
+			}
+		}
+
+		if m.content == paneERD {
+			var eCmd tea.Cmd
+			m.erd, eCmd = m.erd.Update(msg)
+			if eCmd != nil {
+				cmds = append(cmds, eCmd)
+			}
+		}
+
 		if len(cmds) > 0 {
 			return m, tea.Batch(cmds...)
 		}
@@ -216,6 +580,43 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m MainModel) openDDL(o db.Object) (tea.Model, tea.Cmd) {
+	if m.content != paneDDL {
+		m.ddlReturn = m.content
+	}
+	cw, ch := m.dims()
+	m.ddl = NewDDLModel(m.db, o, cw, ch)
+	m.content = paneDDL
+	m.focus = focusContent
+	m.sidebar.focused = false
+	return m, m.ddl.Init()
+}
+
+func (m MainModel) updatePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.picker.switching != "" {
+		return m, nil
+	}
+	switch msg.String() {
+	case "esc":
+		m.picker = nil
+		return m, nil
+	case "enter":
+		name := m.picker.selected()
+		if name == "" {
+			return m, nil
+		}
+		if name == m.cfg.DBName {
+			m.picker = nil
+			return m, nil
+		}
+		m.picker.switching = name
+		m.picker.err = nil
+		return m, switchDatabase(m.db, name)
+	}
+	m.picker.update(msg)
+	return m, nil
+}
+
 var (
 	layoutAccent  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF6F61"))
 	layoutMuted   = lipgloss.NewStyle().Foreground(lipgloss.Color("#8B949E"))
@@ -223,8 +624,22 @@ var (
 	layoutFooter  = lipgloss.NewStyle().Foreground(lipgloss.Color("#555555"))
 	layoutSepNorm = lipgloss.NewStyle().Foreground(lipgloss.Color("#30363D"))
 	layoutSepFoc  = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6F61"))
+
+	healthOKStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#3FB950"))
+	healthWarnStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#E3B341"))
+	healthDownStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5555"))
 )
 
+func (m MainModel) renderHealthBadge() string {
+	switch m.health {
+	case healthReconnecting:
+		return healthWarnStyle.Render("⟳ reconnecting")
+	case healthDown:
+		return healthDownStyle.Render("✕ offline")
+	}
+	return healthOKStyle.Render("●")
+}
+
 func (m MainModel) renderHeader() string {
 	driverIcon := "🐘"
 	if m.cfg.Driver == db.DriverMySQL {
@@ -232,10 +647,16 @@ func (m MainModel) renderHeader() string {
 	}
 	dbName := m.cfg.DBName
 	if dbName == "" {
-		dbName = m.cfg.Host
+		dbName = m.cfg.Endpoint()
+	}
+	left := layoutAccent.Render(" otto") + "  " + m.renderHealthBadge() +
+		layoutMuted.Render("  "+driverIcon+" "+dbName+" @ "+m.cfg.Endpoint())
+	if tag := renderEnvTag(m.cfg); tag != "" {
+		left += "  " + tag
+	}
+	if m.cfg.ReadOnly {
+		left += "  " + histTagROStyle.Render("READ-ONLY")
 	}
-	left := layoutAccent.Render(" otto") +
-		layoutMuted.Render("  ●  "+driverIcon+" "+dbName+" @ "+m.cfg.Host)
 	right := layoutMuted.Render("[s] SQL  [Tab] Switch  [Esc] Disconnect  ")
 
 	gap := m.width - lipgloss.Width(left) - lipgloss.Width(right)
@@ -246,28 +667,82 @@ func (m MainModel) renderHeader() string {
 }
 
 func (m MainModel) renderFooter() string {
+	if m.notice != "" {
+		return healthDownStyle.Render(" ✕ " + m.notice)
+	}
 	var hints string
 	if m.focus == focusSidebar {
 		if m.sidebar.searching {
 			hints = "type to filter  ·  ↑↓ navigate  ·  Enter open  ·  Esc clear search"
 		} else {
-			hints = "↑↓ navigate  ·  Enter open  ·  / search  ·  s SQL  ·  Tab switch  ·  Esc disconnect"
+			hints = "↑↓ navigate  ·  Enter open  ·  ←→ fold  ·  / search  ·  f find value  ·  c DDL  ·  e ER diagram  ·  s SQL  ·  b database  ·  Esc disconnect"
 		}
 	} else {
 		switch m.content {
 		case paneTable:
-			hints = "↑↓ rows  ·  ←→ scroll  ·  a/d column  ·  o sort  ·  u clear  ·  n/p page  ·  r refresh  ·  Tab sidebar  ·  Esc close"
+			if m.table.tab == tabStructure {
+				hints = "↑↓ scroll  ·  ←→ pan  ·  t data  ·  c DDL  ·  r refresh  ·  Tab sidebar  ·  Esc close"
+			} else {
+				hints = "↑↓ rows  ·  ←→ column  ·  / search  ·  < > x X P + - layout  ·  o sort  ·  u clear  ·  n/p page  ·  Enter record  ·  i inspect  ·  S profile  ·  f follow key  ·  t structure  ·  c DDL  ·  Esc close"
+			}
+			if s := m.table.search; s != nil {
+				hints = gridSearchHints(s)
+				if !s.typing {
+					hints += ", then n/p page"
+				}
+			}
+			if m.table.profile != nil {
+				hints = "↑↓ scroll  ·  ←→ previous / next column  ·  Esc back to rows"
+			}
+			if m.table.record {
+				hints = "↑↓ scroll  ·  n/p next / previous row  ·  a/d column  ·  i inspect value  ·  Enter/Esc back to rows"
+			}
+			if m.table.refs != nil {
+				hints = "↑↓ select  ·  Enter open referencing rows  ·  Esc cancel"
+			} else if len(m.tableStack) > 0 || m.tableReturn == paneFinder {
+				hints = strings.Replace(hints, "Esc close", "Esc back", 1)
+			}
+		case paneDDL:
+			hints = "↑↓ scroll  ·  ←→ pan  ·  e open in editor  ·  r refresh  ·  Tab sidebar  ·  Esc back"
+		case paneInspector:
+			if m.cell.searching {
+				hints = "type to search  ·  Enter find  ·  Esc cancel"
+			} else {
+				hints = "↑↓ scroll  ·  ←→ pan  ·  / search  ·  n/N next / previous match  ·  m raw / formatted  ·  Esc back"
+			}
+		case paneFinder:
+			if m.finder.input.Focused() {
+				hints = "type a value  ·  Enter search  ·  Esc back"
+			} else if m.finder.running {
+				hints = "↑↓ select  ·  Enter open table  ·  / new search  ·  Esc stop"
+			} else {
+				hints = "↑↓ select  ·  Enter open table  ·  / new search  ·  Tab sidebar  ·  Esc close"
+			}
+		case paneERD:
+			hints = "↑↓←→ scroll  ·  a all columns  ·  m Diagram/Mermaid/DOT  ·  w write file  ·  e open in editor  ·  r refresh  ·  Esc back"
 		case paneEditor:
-			if m.editor.mode == modeEditing {
+			switch {
+			case m.editor.mode == modeEditing:
 				hints = "Ctrl+E run  ·  Ctrl+R editor↔results  ·  Tab sidebar  ·  Esc sidebar"
-			} else {
-				hints = "↑↓ rows  ·  ←→ scroll  ·  Ctrl+R editor↔results  ·  Tab sidebar  ·  Esc sidebar"
+			case m.editor.record:
+				hints = "↑↓ scroll  ·  n/p next / previous row  ·  a/d column  ·  i inspect value  ·  Enter/Esc back to results"
+			case m.editor.search != nil:
+				hints = gridSearchHints(m.editor.search)
+			default:
+				hints = "↑↓ rows  ·  ←→ column  ·  / search  ·  < > x X P + - layout  ·  Enter record  ·  i inspect  ·  Ctrl+R editor↔results  ·  Tab sidebar  ·  Esc sidebar"
 			}
 		}
 	}
 	return layoutFooter.Render(" " + hints)
 }
 
+func gridSearchHints(s *gridSearch) string {
+	if s.typing {
+		return "type to search  ·  Tab all / selected column  ·  Enter done  ·  Esc cancel"
+	}
+	return "n/N next / previous match  ·  ↑↓ rows  ·  ←→ column  ·  Esc clear search"
+}
+
 func (m MainModel) renderWelcome(w, h int) string {
 	lines := make([]string, h)
 	if h > 2 {
@@ -293,7 +768,11 @@ func (m MainModel) View() string {
 
 	header := m.renderHeader()
 	footer := m.renderFooter()
-	divider := layoutDivider.Render(strings.Repeat("─", w))
+	dividerSty := layoutDivider
+	if env := m.cfg.Environment(); env.IsProduction() {
+		dividerSty = lipgloss.NewStyle().Foreground(lipgloss.Color(env.Color))
+	}
+	divider := dividerSty.Render(strings.Repeat("─", w))
 
 	cw, ch := m.dims()
 
@@ -313,13 +792,23 @@ func (m MainModel) View() string {
 	sep := strings.Join(sepLines, "\n")
 
 	var content string
-	switch m.content {
-	case paneWelcome:
+	switch {
+	case m.picker != nil:
+		content = m.picker.view(cw, ch)
+	case m.content == paneWelcome:
 		content = m.renderWelcome(cw, ch)
-	case paneTable:
+	case m.content == paneTable:
 		content = m.table.ViewPanel(cw, ch)
-	case paneEditor:
+	case m.content == paneEditor:
 		content = m.editor.ViewPanel(cw, ch)
+	case m.content == paneDDL:
+		content = m.ddl.ViewPanel(cw, ch)
+	case m.content == paneERD:
+		content = m.erd.ViewPanel(cw, ch)
+	case m.content == paneInspector:
+		content = m.cell.ViewPanel(cw, ch)
+	case m.content == paneFinder:
+		content = m.finder.ViewPanel(cw, ch)
 	}
 
 	body := lipgloss.JoinHorizontal(lipgloss.Top, sidebarView, sep, content)
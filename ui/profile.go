@@ -0,0 +1,184 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"otto/db"
+)
+
+type profileLoadedMsg struct {
+	session int
+	column  string
+	profile *db.ColumnProfile
+	err     error
+}
+
+type columnProfileView struct {
+	column  string
+	profile *db.ColumnProfile
+	err     error
+	scroll  int
+}
+
+var (
+	profileBarStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6F61"))
+	profileLabelStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#8B949E"))
+)
+
+func (m TableModel) loadProfile(column string) tea.Cmd {
+	d, schema, table, filters, session := m.db, m.schema, m.tableName, m.filters, m.session
+	return func() tea.Msg {
+		p, err := d.ProfileColumn(context.Background(), schema, table, column, filters)
+		return profileLoadedMsg{session: session, column: column, profile: p, err: err}
+	}
+}
+
+func (m TableModel) updateProfile(msg tea.KeyMsg) (TableModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "S":
+		m.profile = nil
+	case "j", "down":
+		if m.profile.profile != nil {
+			maxScroll := max(len(m.profile.lines(m.width))-(m.height-3), 0)
+			m.profile.scroll = min(m.profile.scroll+1, maxScroll)
+		}
+	case "k", "up":
+		m.profile.scroll = max(m.profile.scroll-1, 0)
+	case "l", "right", "d", "h", "left", "a":
+		delta := 1
+		switch msg.String() {
+		case "h", "left", "a":
+			delta = -1
+		}
+		col := m.cols.step(m.colCursor, delta)
+		if col == m.colCursor {
+			return m, nil
+		}
+		m.colCursor = col
+		m.followColumn()
+		m.profile = &columnProfileView{column: m.result.Columns[col]}
+		return m, m.loadProfile(m.profile.column)
+	}
+	return m, nil
+}
+
+func blockBar(n, total int64, w int) string {
+	if total <= 0 || n <= 0 {
+		return ""
+	}
+	eighths := max(int(float64(n)/float64(total)*float64(w*8)), 1)
+	bar := strings.Repeat("█", eighths/8)
+	if r := eighths % 8; r > 0 {
+		bar += string([]rune("▏▎▍▌▋▊▉")[r-1])
+	}
+	return bar
+}
+
+func percent(n, total int64) string {
+	if total == 0 {
+		return "—"
+	}
+	return strconv.FormatFloat(float64(n)*100/float64(total), 'f', 1, 64) + "%"
+}
+
+func formatBound(f float64) string {
+	if f == math.Trunc(f) && math.Abs(f) < 1e15 {
+		return strconv.FormatFloat(f, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(f, 'g', 4, 64)
+}
+
+// Lengths are whole numbers, so their bins are inclusive integer ranges.
+func binLabel(p *db.ColumnProfile, b db.HistogramBin) string {
+	switch {
+	case b.Low == b.High:
+		return formatBound(b.Low)
+	case p.HistogramOf != "length":
+		return formatBound(b.Low) + " – " + formatBound(b.High)
+	}
+	lo, hi := int64(math.Ceil(b.Low)), int64(math.Ceil(b.High))-1
+	if hi <= lo {
+		return strconv.FormatInt(lo, 10)
+	}
+	return fmt.Sprintf("%d – %d", lo, hi)
+}
+
+func (v columnProfileView) lines(w int) []string {
+	p := v.profile
+	stat := func(name, value string) string {
+		return profileLabelStyle.Render(fmt.Sprintf("   %-12s", name)) + tblRowStyle.Render(clipLine(value, max(w-15, 1)))
+	}
+	nonNull := p.Rows - p.Nulls
+	out := []string{
+		stat("rows", strconv.FormatInt(p.Rows, 10)),
+		stat("nulls", fmt.Sprintf("%d  (%s)", p.Nulls, percent(p.Nulls, p.Rows))),
+		stat("distinct", fmt.Sprintf("%d  (%s of non-null)", p.Distinct, percent(p.Distinct, nonNull))),
+	}
+	if nonNull > 0 {
+		out = append(out,
+			stat("min", strings.Join(strings.Fields(p.Min), " ")),
+			stat("max", strings.Join(strings.Fields(p.Max), " ")),
+			stat("avg length", strconv.FormatFloat(p.AvgLength, 'f', 1, 64)))
+	}
+
+	if len(p.Top) > 0 {
+		out = append(out, "", structSectionStyle.Render(clipLine(fmt.Sprintf(" TOP %d VALUES", len(p.Top)), w)))
+		valueW := 0
+		for _, t := range p.Top {
+			valueW = max(valueW, len([]rune(t.Value)))
+		}
+		valueW = min(valueW, max(w/3, 10))
+		for _, t := range p.Top {
+			value := clipLine(strings.Join(strings.Fields(t.Value), " "), valueW)
+			label := fmt.Sprintf("   %-*s  %8d  %6s  ", valueW, value, t.Count, percent(t.Count, nonNull))
+			out = append(out, tblRowStyle.Render(label)+profileBarStyle.Render(blockBar(t.Count, p.Top[0].Count, max(w-len([]rune(label))-1, 1))))
+		}
+	}
+
+	if len(p.Histogram) > 0 {
+		out = append(out, "", structSectionStyle.Render(clipLine(" "+strings.ToUpper(p.HistogramOf)+" HISTOGRAM", w)))
+		var peak int64
+		labels := make([]string, len(p.Histogram))
+		labelW := 0
+		for i, b := range p.Histogram {
+			peak = max(peak, b.Count)
+			labels[i] = binLabel(p, b)
+			labelW = max(labelW, len([]rune(labels[i])))
+		}
+		for i, b := range p.Histogram {
+			label := fmt.Sprintf("   %-*s  %8d  ", labelW, labels[i], b.Count)
+			out = append(out, tblRowStyle.Render(label)+profileBarStyle.Render(blockBar(b.Count, peak, max(w-len([]rune(label))-1, 1))))
+		}
+	}
+	return out
+}
+
+func (v columnProfileView) view(w, h int) string {
+	var b strings.Builder
+	title := " PROFILE  " + v.column
+	if v.profile != nil && v.profile.DataType != "" {
+		title += "  " + v.profile.DataType
+	}
+	b.WriteString(structSectionStyle.Render(clipLine(title, w)) + "\n")
+	switch {
+	case v.err != nil:
+		errSty := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000"))
+		b.WriteString(errSty.Render(clipLine(fmt.Sprintf(" Error: %v", v.err), w)))
+		return b.String()
+	case v.profile == nil:
+		b.WriteString(" Profiling...")
+		return b.String()
+	}
+	lines := v.lines(w)
+	visible := max(h-1, 1)
+	start := min(v.scroll, max(len(lines)-visible, 0))
+	end := min(start+visible, len(lines))
+	b.WriteString(strings.Join(lines[start:end], "\n"))
+	return b.String()
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	recordNameStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#8B949E"))
+	recordNullStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#555555")).Italic(true)
+)
+
+type recordLine struct {
+	name    string
+	value   string
+	null    bool
+	current bool
+}
+
+func wrapRunes(s string, w int) []string {
+	var out []string
+	for _, line := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		runes := []rune(strings.ReplaceAll(line, "\t", "    "))
+		for len(runes) > w {
+			out = append(out, string(runes[:w]))
+			runes = runes[w:]
+		}
+		out = append(out, string(runes))
+	}
+	return out
+}
+
+func recordLines(columns, row []string, current, nameW, valueW int) []recordLine {
+	var out []recordLine
+	for i, col := range columns {
+		val := ""
+		if i < len(row) {
+			val = row[i]
+		}
+		for j, part := range wrapRunes(val, valueW) {
+			l := recordLine{value: part, null: val == "NULL", current: i == current}
+			if j == 0 {
+				l.name = col
+			}
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+func recordWidths(columns []string, w int) (int, int) {
+	nameW := 0
+	for _, c := range columns {
+		nameW = max(nameW, len([]rune(c)))
+	}
+	nameW = min(nameW, 30)
+	return nameW, max(w-nameW-4, 10)
+}
+
+func recordLineCount(columns, row []string, w int) int {
+	nameW, valueW := recordWidths(columns, w)
+	return len(recordLines(columns, row, -1, nameW, valueW))
+}
+
+func renderRecord(title string, columns, row []string, current, scroll, w, h int) string {
+	var b strings.Builder
+	b.WriteString(title + "\n\n")
+	nameW, valueW := recordWidths(columns, w)
+	lines := recordLines(columns, row, current, nameW, valueW)
+	visible := max(h-2, 1)
+	start := min(scroll, max(len(lines)-visible, 0))
+	end := min(start+visible, len(lines))
+	for _, l := range lines[start:end] {
+		name := " " + padRight(l.name, nameW)
+		if l.current {
+			b.WriteString(tblSelStyle.Render(padRight(name+" │ "+l.value, w)) + "\n")
+			continue
+		}
+		valStyle := tblRowStyle
+		if l.null {
+			valStyle = recordNullStyle
+		}
+		b.WriteString(recordNameStyle.Render(name) + tblBorderStyle.Render(" │ ") + valStyle.Render(l.value) + "\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
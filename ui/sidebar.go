@@ -2,6 +2,7 @@ package ui
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -9,95 +10,439 @@ import (
 	"otto/db"
 )
 
-type sidebarTablesMsg struct {
-	tables []db.Table
+type sidebarSchemasMsg struct {
+	session int
+	schemas []string
+	err     error
 }
 
-type sidebarErrMsg struct {
-	err error
+type sidebarObjectsMsg struct {
+	session int
+	schema  string
+	kind    db.ObjectKind
+	objects []db.Object
+	err     error
+}
+
+type sidebarColumnsMsg struct {
+	session int
+	key     string
+	columns []db.Column
+	err     error
+}
+
+type treeNode int
+
+const (
+	nodeSchema treeNode = iota
+	nodeGroup
+	nodeObject
+	nodeColumn
+	nodeInfo
+)
+
+type treeRow struct {
+	node   treeNode
+	level  int
+	schema string
+	kind   db.ObjectKind
+	object db.Object
+	column db.Column
+	text   string
+	isErr  bool
+}
+
+func (r treeRow) key() string {
+	switch r.node {
+	case nodeSchema:
+		return r.schema
+	case nodeGroup:
+		return groupKey(r.schema, r.kind)
+	case nodeObject:
+		return objectKey(r.object)
+	case nodeColumn:
+		return objectKey(r.object) + "\x00" + r.column.Name
+	}
+	return ""
+}
+
+func objectKey(o db.Object) string {
+	return groupKey(o.Schema, o.Kind) + "\x00" + o.Name + "\x00" + o.Detail
+}
+
+func groupKey(schema string, kind db.ObjectKind) string {
+	return schema + "\x00" + string(kind)
+}
+
+var kindLabels = map[db.ObjectKind]string{
+	db.KindTable:            "Tables",
+	db.KindView:             "Views",
+	db.KindMaterializedView: "Mat. views",
+	db.KindIndex:            "Indexes",
+	db.KindSequence:         "Sequences",
+	db.KindFunction:         "Functions",
+	db.KindTrigger:          "Triggers",
+}
+
+var kindIcons = map[db.ObjectKind]string{
+	db.KindTable:            "▦",
+	db.KindView:             "◫",
+	db.KindMaterializedView: "◩",
+	db.KindIndex:            "≡",
+	db.KindSequence:         "#",
+	db.KindFunction:         "ƒ",
+	db.KindProcedure:        "λ",
+	db.KindTrigger:          "↯",
 }
 
 type SidebarModel struct {
-	db        db.DB
-	cfg       db.Config
-	tables    []db.Table
-	filtered  []db.Table
-	cursor    int
-	width     int
-	height    int
-	focused   bool
-	loading   bool
-	searching bool
-	query     string
-}
-
-func NewSidebarModel(d db.DB, cfg db.Config, width, height int) SidebarModel {
+	db         db.DB
+	cfg        db.Config
+	kinds      []db.ObjectKind
+	schemas    []string
+	objects    map[string][]db.Object
+	columns    map[string][]db.Column
+	expanded   map[string]bool
+	pending    map[string]bool
+	errs       map[string]error
+	rows       []treeRow
+	autoSelect string
+	err        error
+	cursor     int
+	width      int
+	height     int
+	focused    bool
+	loading    bool
+	searching  bool
+	query      string
+	session    int
+}
+
+func NewSidebarModel(d db.DB, cfg db.Config, width, height, session int) SidebarModel {
 	return SidebarModel{
-		db:      d,
-		cfg:     cfg,
-		width:   width,
-		height:  height,
-		loading: true,
-		focused: true,
+		db:       d,
+		cfg:      cfg,
+		kinds:    db.ObjectKinds(cfg.Driver),
+		objects:  map[string][]db.Object{},
+		columns:  map[string][]db.Column{},
+		expanded: map[string]bool{},
+		pending:  map[string]bool{},
+		errs:     map[string]error{},
+		width:    width,
+		height:   height,
+		loading:  true,
+		focused:  true,
+		session:  session,
+	}
+}
+
+func (m SidebarModel) loadSchemas() tea.Msg {
+	schemas, err := m.db.ListSchemas(context.Background())
+	return sidebarSchemasMsg{session: m.session, schemas: schemas, err: err}
+}
+
+func (m SidebarModel) loadObjects(schema string, kind db.ObjectKind) tea.Cmd {
+	m.pending[groupKey(schema, kind)] = true
+	session := m.session
+	return func() tea.Msg {
+		objects, err := db.ListObjects(context.Background(), m.db, schema, kind)
+		return sidebarObjectsMsg{session: session, schema: schema, kind: kind, objects: objects, err: err}
 	}
 }
 
-func (m SidebarModel) loadTables() tea.Msg {
-	tables, err := m.db.ListTables(context.Background())
-	if err != nil {
-		return sidebarErrMsg{err: err}
+func (m SidebarModel) loadColumns(o db.Object) tea.Cmd {
+	key := objectKey(o)
+	m.pending[key] = true
+	session := m.session
+	return func() tea.Msg {
+		cols, err := m.db.ListTableColumns(context.Background(), o.Schema, o.Name)
+		return sidebarColumnsMsg{session: session, key: key, columns: cols, err: err}
 	}
-	return sidebarTablesMsg{tables: tables}
 }
 
 func (m SidebarModel) Init() tea.Cmd {
-	return m.loadTables
+	return m.loadSchemas
 }
 
-func (m *SidebarModel) applyFilter() {
-	if m.query == "" {
-		m.filtered = m.tables
-		return
+func (m SidebarModel) defaultSchema() string {
+	for _, s := range m.schemas {
+		if s == "public" || s == m.cfg.DBName {
+			return s
+		}
 	}
+	if len(m.schemas) == 1 {
+		return m.schemas[0]
+	}
+	return ""
+}
+
+func (m *SidebarModel) rebuild() {
+	var prev string
+	if m.cursor >= 0 && m.cursor < len(m.rows) {
+		prev = m.rows[m.cursor].key()
+	}
+
 	q := strings.ToLower(m.query)
-	m.filtered = nil
-	for _, t := range m.tables {
-		if strings.Contains(strings.ToLower(t.Name), q) {
-			m.filtered = append(m.filtered, t)
+	filtering := q != ""
+	m.rows = nil
+	for _, schema := range m.schemas {
+		var groups []treeRow
+		for _, kind := range m.kinds {
+			key := groupKey(schema, kind)
+			group := treeRow{node: nodeGroup, level: 1, schema: schema, kind: kind}
+			var children []treeRow
+			for _, o := range m.objects[key] {
+				if filtering && !strings.Contains(strings.ToLower(o.Name), q) {
+					continue
+				}
+				children = append(children, treeRow{node: nodeObject, level: 2, schema: schema, kind: kind, object: o})
+				children = append(children, m.columnRows(schema, kind, o)...)
+			}
+			if filtering {
+				if len(children) > 0 {
+					groups = append(groups, group)
+					groups = append(groups, children...)
+				}
+				continue
+			}
+			groups = append(groups, group)
+			if !m.expanded[key] {
+				continue
+			}
+			switch {
+			case m.errs[key] != nil:
+				groups = append(groups, treeRow{node: nodeInfo, level: 2, text: m.errs[key].Error(), isErr: true})
+			case m.pending[key] && m.objects[key] == nil:
+				groups = append(groups, treeRow{node: nodeInfo, level: 2, text: "loading..."})
+			case len(children) == 0:
+				groups = append(groups, treeRow{node: nodeInfo, level: 2, text: "none"})
+			default:
+				groups = append(groups, children...)
+			}
 		}
+		if filtering && len(groups) == 0 {
+			continue
+		}
+		m.rows = append(m.rows, treeRow{node: nodeSchema, schema: schema})
+		if filtering || m.expanded[schema] {
+			m.rows = append(m.rows, groups...)
+		}
+	}
+
+	for i, r := range m.rows {
+		if prev != "" && r.key() == prev {
+			m.cursor = i
+			return
+		}
+	}
+	if m.cursor >= len(m.rows) {
+		m.cursor = max(len(m.rows)-1, 0)
+	}
+}
+
+func (m SidebarModel) columnRows(schema string, kind db.ObjectKind, o db.Object) []treeRow {
+	key := objectKey(o)
+	if !m.expanded[key] {
+		return nil
+	}
+	switch {
+	case m.errs[key] != nil:
+		return []treeRow{{node: nodeInfo, level: 3, text: m.errs[key].Error(), isErr: true}}
+	case m.pending[key] && m.columns[key] == nil:
+		return []treeRow{{node: nodeInfo, level: 3, text: "loading..."}}
+	case len(m.columns[key]) == 0:
+		return []treeRow{{node: nodeInfo, level: 3, text: "no columns"}}
+	}
+	rows := make([]treeRow, len(m.columns[key]))
+	for i, c := range m.columns[key] {
+		rows[i] = treeRow{node: nodeColumn, level: 3, schema: schema, kind: kind, object: o, column: c}
+	}
+	return rows
+}
+
+func (m SidebarModel) expandable(r treeRow) bool {
+	switch r.node {
+	case nodeSchema, nodeGroup:
+		return true
+	case nodeObject:
+		return r.object.Kind.HasRows()
+	}
+	return false
+}
+
+func (m SidebarModel) isExpanded(r treeRow) bool {
+	if m.query != "" && r.node != nodeObject {
+		return true
+	}
+	return m.expanded[r.key()]
+}
+
+func (m *SidebarModel) expand(r treeRow) tea.Cmd {
+	m.expanded[r.key()] = true
+	var cmds []tea.Cmd
+	switch r.node {
+	case nodeSchema:
+		for _, kind := range m.kinds {
+			if m.expanded[groupKey(r.schema, kind)] {
+				cmds = append(cmds, m.ensureLoaded(r.schema, kind))
+			}
+		}
+	case nodeGroup:
+		cmds = append(cmds, m.ensureLoaded(r.schema, r.kind))
+	case nodeObject:
+		key := objectKey(r.object)
+		if m.columns[key] == nil && !m.pending[key] {
+			delete(m.errs, key)
+			cmds = append(cmds, m.loadColumns(r.object))
+		}
+	}
+	m.rebuild()
+	return tea.Batch(cmds...)
+}
+
+func (m *SidebarModel) ensureLoaded(schema string, kind db.ObjectKind) tea.Cmd {
+	key := groupKey(schema, kind)
+	if m.objects[key] != nil || m.pending[key] {
+		return nil
+	}
+	delete(m.errs, key)
+	return m.loadObjects(schema, kind)
+}
+
+func (m *SidebarModel) collapseOrParent() {
+	r := m.rows[m.cursor]
+	if m.expandable(r) && m.isExpanded(r) && (m.query == "" || r.node == nodeObject) {
+		delete(m.expanded, r.key())
+		m.rebuild()
+		return
+	}
+	for i := m.cursor - 1; i >= 0; i-- {
+		if m.rows[i].level < r.level && m.rows[i].node != nodeInfo {
+			m.cursor = i
+			return
+		}
+	}
+}
+
+func (m *SidebarModel) searchAll() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, schema := range m.schemas {
+		cmds = append(cmds, m.ensureLoaded(schema, db.KindTable))
 	}
+	return tea.Batch(cmds...)
 }
 
 func (m SidebarModel) Update(msg tea.Msg) (SidebarModel, tea.Cmd) {
 	switch msg := msg.(type) {
-	case sidebarTablesMsg:
-		m.tables = msg.tables
-		m.filtered = msg.tables
+	case sidebarSchemasMsg:
+		if msg.session != m.session {
+			return m, nil
+		}
 		m.loading = false
+		m.err = msg.err
+		if msg.err != nil {
+			return m, nil
+		}
+		first := m.schemas == nil
+		m.schemas = msg.schemas
+		if m.schemas == nil {
+			m.schemas = []string{}
+		}
+		if first {
+			if s := m.defaultSchema(); s != "" {
+				m.expanded[s] = true
+				m.expanded[groupKey(s, db.KindTable)] = true
+				m.autoSelect = groupKey(s, db.KindTable)
+			}
+		}
+		var cmds []tea.Cmd
+		for _, schema := range m.schemas {
+			for _, kind := range m.kinds {
+				key := groupKey(schema, kind)
+				switch {
+				case !m.expanded[schema] || !m.expanded[key]:
+					delete(m.objects, key)
+				case !m.pending[key]:
+					delete(m.errs, key)
+					cmds = append(cmds, m.loadObjects(schema, kind))
+				}
+			}
+		}
+		for key := range m.columns {
+			delete(m.columns, key)
+		}
+		m.rebuild()
+		return m, tea.Batch(cmds...)
 
-	case sidebarErrMsg:
-		m.loading = false
+	case sidebarObjectsMsg:
+		if msg.session != m.session {
+			return m, nil
+		}
+		key := groupKey(msg.schema, msg.kind)
+		delete(m.pending, key)
+		if msg.err != nil {
+			m.errs[key] = msg.err
+		} else {
+			if msg.objects == nil {
+				msg.objects = []db.Object{}
+			}
+			m.objects[key] = msg.objects
+		}
+		var cmds []tea.Cmd
+		for _, o := range m.objects[key] {
+			ok := objectKey(o)
+			if m.expanded[ok] && m.columns[ok] == nil && !m.pending[ok] {
+				cmds = append(cmds, m.loadColumns(o))
+			}
+		}
+		m.rebuild()
+		if key == m.autoSelect {
+			m.autoSelect = ""
+			for i, r := range m.rows {
+				if r.node == nodeObject && groupKey(r.schema, r.kind) == key {
+					m.cursor = i
+					break
+				}
+			}
+		}
+		return m, tea.Batch(cmds...)
+
+	case sidebarColumnsMsg:
+		if msg.session != m.session {
+			return m, nil
+		}
+		delete(m.pending, msg.key)
+		if msg.err != nil {
+			m.errs[msg.key] = msg.err
+		} else {
+			if msg.columns == nil {
+				msg.columns = []db.Column{}
+			}
+			m.columns[msg.key] = msg.columns
+		}
+		m.rebuild()
 
 	case tea.KeyMsg:
 		if !m.focused {
 			return m, nil
 		}
+		m.autoSelect = ""
 
 		if m.searching {
 			switch msg.Type {
 			case tea.KeyEsc:
 				m.searching = false
 				m.query = ""
-				m.filtered = m.tables
-				m.cursor = 0
+				m.rebuild()
 			case tea.KeyBackspace:
 				if len(m.query) > 0 {
-					m.query = m.query[:len(m.query)-1]
-					m.applyFilter()
+					r := []rune(m.query)
+					m.query = string(r[:len(r)-1])
 					m.cursor = 0
+					m.rebuild()
 				}
 			case tea.KeyDown:
-				if m.cursor < len(m.filtered)-1 {
+				if m.cursor < len(m.rows)-1 {
 					m.cursor++
 				}
 			case tea.KeyUp:
@@ -106,8 +451,9 @@ func (m SidebarModel) Update(msg tea.Msg) (SidebarModel, tea.Cmd) {
 				}
 			case tea.KeyRunes:
 				m.query += string(msg.Runes)
-				m.applyFilter()
 				m.cursor = 0
+				m.rebuild()
+				m.selectFirstObject()
 			}
 			return m, nil
 		}
@@ -116,27 +462,75 @@ func (m SidebarModel) Update(msg tea.Msg) (SidebarModel, tea.Cmd) {
 		case "/":
 			m.searching = true
 			m.query = ""
-			m.applyFilter()
-			m.cursor = 0
+			m.rebuild()
+			return m, m.searchAll()
 		case "j", "down":
-			if m.cursor < len(m.filtered)-1 {
+			if m.cursor < len(m.rows)-1 {
 				m.cursor++
 			}
 		case "k", "up":
 			if m.cursor > 0 {
 				m.cursor--
 			}
+		case "enter", " ":
+			if len(m.rows) == 0 {
+				return m, nil
+			}
+			r := m.rows[m.cursor]
+			if !m.expandable(r) {
+				return m, nil
+			}
+			if m.isExpanded(r) {
+				if m.query == "" || r.node == nodeObject {
+					delete(m.expanded, r.key())
+					m.rebuild()
+				}
+				return m, nil
+			}
+			return m, m.expand(r)
+		case "l", "right":
+			if len(m.rows) == 0 {
+				return m, nil
+			}
+			r := m.rows[m.cursor]
+			if m.expandable(r) && !m.isExpanded(r) {
+				return m, m.expand(r)
+			}
+		case "h", "left":
+			if len(m.rows) > 0 {
+				m.collapseOrParent()
+			}
 		}
 	}
 	return m, nil
 }
 
-func (m SidebarModel) SelectedTable() *db.Table {
-	if len(m.filtered) == 0 || m.cursor >= len(m.filtered) {
+func (m *SidebarModel) selectFirstObject() {
+	for i, r := range m.rows {
+		if r.node == nodeObject {
+			m.cursor = i
+			return
+		}
+	}
+}
+
+func (m SidebarModel) SelectedObject() *db.Object {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
 		return nil
 	}
-	t := m.filtered[m.cursor]
-	return &t
+	r := m.rows[m.cursor]
+	if r.node != nodeObject && r.node != nodeColumn {
+		return nil
+	}
+	o := r.object
+	return &o
+}
+
+func (m SidebarModel) SelectedSchema() string {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return ""
+	}
+	return m.rows[m.cursor].schema
 }
 
 var (
@@ -165,8 +559,95 @@ var (
 	sidebarNoMatchStyle = lipgloss.NewStyle().
 				Foreground(lipgloss.Color("#555555")).
 				Italic(true)
+
+	sidebarSchemaStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#E6EDF3"))
+
+	sidebarCountStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#555555"))
 )
 
+var typeAbbreviations = []struct{ long, short string }{
+	{"character varying", "varchar"},
+	{"character", "char"},
+	{"timestamp without time zone", "timestamp"},
+	{"timestamp with time zone", "timestamptz"},
+	{"time without time zone", "time"},
+	{"time with time zone", "timetz"},
+	{"double precision", "float8"},
+	{"bit varying", "varbit"},
+}
+
+func shortTypeName(t string) string {
+	for _, a := range typeAbbreviations {
+		if strings.HasPrefix(t, a.long) {
+			return a.short + t[len(a.long):]
+		}
+	}
+	return t
+}
+
+func (m SidebarModel) renderRow(r treeRow, selected bool, w int) string {
+	indent := strings.Repeat("  ", r.level)
+	arrow := "▸ "
+	if m.isExpanded(r) {
+		arrow = "▾ "
+	}
+
+	var label, suffix string
+	style := sidebarItemStyle
+	switch r.node {
+	case nodeSchema:
+		label = arrow + "◆ " + r.schema
+		style = sidebarSchemaStyle
+	case nodeGroup:
+		label = arrow + kindIcons[r.kind] + " " + kindLabels[r.kind]
+		if objs, ok := m.objects[groupKey(r.schema, r.kind)]; ok {
+			suffix = fmt.Sprintf(" %d", len(objs))
+		}
+	case nodeObject:
+		if !m.expandable(r) {
+			arrow = "  "
+		}
+		label = arrow + kindIcons[r.object.Kind] + " " + r.object.Name
+		if r.object.Detail != "" {
+			suffix = " " + r.object.Detail
+		}
+	case nodeColumn:
+		icon := "·"
+		if r.column.PrimaryKey {
+			icon = "⚷"
+		}
+		label = "  " + icon + " " + r.column.Name
+		suffix = " " + shortTypeName(r.column.DataType)
+		if r.column.Nullable {
+			suffix += "?"
+		}
+	case nodeInfo:
+		label = "  " + r.text
+		style = sidebarNoMatchStyle
+		if r.isErr {
+			style = errStyle
+		}
+	}
+
+	avail := w - 2 - lipgloss.Width(indent)
+	label = clipRunes(label, avail)
+	if rest := avail - lipgloss.Width(label); lipgloss.Width(suffix) > rest {
+		suffix = ""
+		if rest >= 5 && r.node == nodeColumn {
+			suffix = clipRunes(" "+shortTypeName(r.column.DataType), rest)
+		}
+	}
+
+	prefix := " "
+	if selected {
+		prefix = "▶"
+		style = sidebarSelectedStyle
+	}
+	return style.Render(prefix+indent+label) + sidebarCountStyle.Render(suffix)
+}
+
 func (m SidebarModel) View() string {
 	w := m.width
 	if w < 6 {
@@ -180,15 +661,11 @@ func (m SidebarModel) View() string {
 	if m.focused {
 		titleSty = sidebarFocusTitleStyle
 	}
-	lines = append(lines, titleSty.Render(" TABLES"))
+	lines = append(lines, titleSty.Render(" OBJECTS"))
 
 	if m.searching {
-		cursor := "█"
-		if len(m.query) == 0 {
-			cursor = "█"
-		}
 		searchLine := sidebarSearchLabelStyle.Render(" /") +
-			sidebarSearchStyle.Render(m.query+cursor)
+			sidebarSearchStyle.Render(m.query+"█")
 		lines = append(lines, searchLine)
 	} else {
 		hint := ""
@@ -199,14 +676,16 @@ func (m SidebarModel) View() string {
 			lipgloss.NewStyle().Foreground(lipgloss.Color("#333344")).Render(hint))
 	}
 
-	list := m.filtered
-	if m.loading {
+	switch {
+	case m.loading:
 		lines = append(lines, sidebarItemStyle.Render("  loading..."))
-	} else if len(list) == 0 && m.query != "" {
+	case m.err != nil:
+		lines = append(lines, errStyle.Render(lipgloss.NewStyle().Width(w-2).Render("  "+m.err.Error())))
+	case len(m.rows) == 0 && m.query != "":
 		lines = append(lines, sidebarNoMatchStyle.Render("  no match"))
-	} else if len(list) == 0 {
-		lines = append(lines, sidebarItemStyle.Render("  no tables"))
-	} else {
+	case len(m.rows) == 0:
+		lines = append(lines, sidebarItemStyle.Render("  no schemas"))
+	default:
 		contentH := h - 2
 		if contentH < 1 {
 			contentH = 1
@@ -215,29 +694,9 @@ func (m SidebarModel) View() string {
 		if m.cursor >= contentH {
 			start = m.cursor - contentH + 1
 		}
-		end := start + contentH
-		if end > len(list) {
-			end = len(list)
-		}
+		end := min(start+contentH, len(m.rows))
 		for i := start; i < end; i++ {
-			t := list[i]
-			name := t.Name
-			if m.cfg.DBName == "" && t.Schema != "" {
-				name = t.Schema + "." + t.Name
-			}
-			maxLen := w - 4
-			if maxLen < 1 {
-				maxLen = 1
-			}
-			runes := []rune(name)
-			if len(runes) > maxLen {
-				name = string(runes[:maxLen])
-			}
-			if i == m.cursor {
-				lines = append(lines, sidebarSelectedStyle.Render(" ▶ "+name))
-			} else {
-				lines = append(lines, sidebarItemStyle.Render("   "+name))
-			}
+			lines = append(lines, m.renderRow(m.rows[i], i == m.cursor, w))
 		}
 	}
 
@@ -0,0 +1,219 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"otto/db"
+)
+
+type tableTab int
+
+const (
+	tabData tableTab = iota
+	tabStructure
+)
+
+type tableStructure struct {
+	columns     []db.Column
+	indexes     []db.Index
+	constraints []db.Constraint
+	foreignKeys []db.ForeignKey
+	referencing []db.ForeignKey
+	triggers    []db.Trigger
+}
+
+type structureLoadedMsg struct {
+	session   int
+	structure *tableStructure
+}
+
+type structureErrMsg struct {
+	session int
+	err     error
+}
+
+func (m TableModel) loadStructure() tea.Msg {
+	ctx := context.Background()
+	var (
+		s   tableStructure
+		err error
+	)
+	if s.columns, err = m.db.ListTableColumns(ctx, m.schema, m.tableName); err != nil {
+		return structureErrMsg{session: m.session, err: err}
+	}
+	if s.indexes, err = m.db.ListIndexes(ctx, m.schema, m.tableName); err != nil {
+		return structureErrMsg{session: m.session, err: err}
+	}
+	if s.constraints, err = m.db.ListConstraints(ctx, m.schema, m.tableName); err != nil {
+		return structureErrMsg{session: m.session, err: err}
+	}
+	if s.foreignKeys, err = m.db.ListForeignKeys(ctx, m.schema, m.tableName); err != nil {
+		return structureErrMsg{session: m.session, err: err}
+	}
+	if s.referencing, err = m.db.ListReferencingKeys(ctx, m.schema, m.tableName); err != nil {
+		return structureErrMsg{session: m.session, err: err}
+	}
+	if s.triggers, err = m.db.ListTableTriggers(ctx, m.schema, m.tableName); err != nil {
+		return structureErrMsg{session: m.session, err: err}
+	}
+	return structureLoadedMsg{session: m.session, structure: &s}
+}
+
+func formatBytes(n int64) string {
+	if n < 0 {
+		return "—"
+	}
+	units := []string{"B", "kB", "MB", "GB", "TB"}
+	f := float64(n)
+	i := 0
+	for f >= 1024 && i < len(units)-1 {
+		f /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%d B", n)
+	}
+	return fmt.Sprintf("%.1f %s", f, units[i])
+}
+
+func qualifiedName(schema, name string) string {
+	if schema == "" {
+		return name
+	}
+	return schema + "." + name
+}
+
+func gridLines(rows [][]string) []string {
+	if len(rows) == 0 {
+		return nil
+	}
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len([]rune(cell)))
+		}
+	}
+	lines := make([]string, len(rows))
+	for r, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = padRight(cell, widths[i])
+		}
+		lines[r] = "   " + strings.TrimRight(strings.Join(cells, "  "), " ")
+	}
+	return lines
+}
+
+var (
+	structSectionStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#E6EDF3"))
+	structHeadStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#8B949E"))
+)
+
+type structLine struct {
+	text  string
+	style lipgloss.Style
+}
+
+func (s *tableStructure) lines() []structLine {
+	var out []structLine
+	section := func(title string, n int, head []string, rows [][]string) {
+		if len(out) > 0 {
+			out = append(out, structLine{style: tblRowStyle})
+		}
+		out = append(out, structLine{text: fmt.Sprintf(" %s (%d)", title, n), style: structSectionStyle})
+		if n == 0 {
+			out = append(out, structLine{text: "   none", style: sidebarNoMatchStyle})
+			return
+		}
+		for i, line := range gridLines(append([][]string{head}, rows...)) {
+			style := tblRowStyle
+			if i == 0 {
+				style = structHeadStyle
+			}
+			out = append(out, structLine{text: line, style: style})
+		}
+	}
+
+	var rows [][]string
+	for _, c := range s.columns {
+		null, key := "NOT NULL", "  "
+		if c.Nullable {
+			null = "NULL"
+		}
+		if c.PrimaryKey {
+			key = "⚷ "
+		}
+		rows = append(rows, []string{fmt.Sprint(c.Position), key + c.Name, c.DataType, null, c.Default, c.Comment})
+	}
+	section("COLUMNS", len(s.columns), []string{"#", "name", "type", "null", "default", "comment"}, rows)
+
+	rows = nil
+	for _, ix := range s.indexes {
+		kind := "INDEX"
+		switch {
+		case ix.Primary:
+			kind = "PRIMARY"
+		case ix.Unique:
+			kind = "UNIQUE"
+		}
+		rows = append(rows, []string{ix.Name, kind, strings.Join(ix.Columns, ", "), formatBytes(ix.Size), ix.Definition})
+	}
+	section("INDEXES", len(s.indexes), []string{"name", "kind", "columns", "size", "definition"}, rows)
+
+	rows = nil
+	for _, c := range s.constraints {
+		rows = append(rows, []string{c.Name, string(c.Kind), c.Definition})
+	}
+	section("CONSTRAINTS", len(s.constraints), []string{"name", "kind", "definition"}, rows)
+
+	rows = nil
+	for _, fk := range s.foreignKeys {
+		rows = append(rows, []string{fk.Name, "(" + strings.Join(fk.Columns, ", ") + ")",
+			"→ " + qualifiedName(fk.RefSchema, fk.RefTable) + "(" + strings.Join(fk.RefColumns, ", ") + ")",
+			"ON UPDATE " + fk.OnUpdate, "ON DELETE " + fk.OnDelete})
+	}
+	section("FOREIGN KEYS", len(s.foreignKeys), []string{"name", "columns", "references", "update", "delete"}, rows)
+
+	rows = nil
+	for _, fk := range s.referencing {
+		rows = append(rows, []string{qualifiedName(fk.Schema, fk.Table) + "(" + strings.Join(fk.Columns, ", ") + ")",
+			"→ (" + strings.Join(fk.RefColumns, ", ") + ")", fk.Name, "ON DELETE " + fk.OnDelete})
+	}
+	section("REFERENCED BY", len(s.referencing), []string{"table", "columns", "constraint", "delete"}, rows)
+
+	rows = nil
+	for _, t := range s.triggers {
+		rows = append(rows, []string{t.Name, t.Timing, t.Events, strings.Join(strings.Fields(t.Definition), " ")})
+	}
+	section("TRIGGERS", len(s.triggers), []string{"name", "timing", "events", "definition"}, rows)
+
+	return out
+}
+
+func (m TableModel) viewStructure(w, h int) string {
+	var b strings.Builder
+	b.WriteString(m.renderTitle("") + "\n\n")
+
+	switch {
+	case m.structErr != nil:
+		errSty := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000"))
+		b.WriteString(errSty.Render(fmt.Sprintf(" Error: %v", m.structErr)))
+		return b.String()
+	case m.structure == nil:
+		b.WriteString(" Loading...")
+		return b.String()
+	}
+
+	lines := m.structure.lines()
+	visible := max(h-2, 1)
+	start := min(m.structScroll, max(len(lines)-visible, 0))
+	end := min(start+visible, len(lines))
+	for _, l := range lines[start:end] {
+		b.WriteString(l.style.Render(clipLine(truncateLine(l.text, m.scrollX, w), w)) + "\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -3,6 +3,7 @@ package ui
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -13,11 +14,14 @@ import (
 const pageSize = 50
 
 type dataLoadedMsg struct {
-	result *db.QueryResult
+	session int
+	result  *db.QueryResult
+	layout  db.ColumnLayout
 }
 
 type dataErrMsg struct {
-	err error
+	session int
+	err     error
 }
 
 type GoBackMsg struct{}
@@ -26,27 +30,47 @@ type TableModel struct {
 	db        db.DB
 	schema    string
 	tableName string
+	kind      db.ObjectKind
 	result    *db.QueryResult
 	err       error
 	cursor    int
 	offset    int
 	scrollX   int
+	colOffset int
 	width     int
 	height    int
 	colWidths []int
+	cols      columnLayout
+	layoutKey string
 	sortCol   int
 	sortDesc  bool
 	colCursor int
+	filters   []db.ColumnFilter
+	session   int
+	refs      *referenceList
+	search    *gridSearch
+	profile   *columnProfileView
+
+	record       bool
+	recordScroll int
+	recordLast   bool
+
+	tab          tableTab
+	structure    *tableStructure
+	structErr    error
+	structScroll int
 }
 
-func NewTableModel(d db.DB, schema, name string, width, height int) TableModel {
+func NewTableModel(d db.DB, schema, name string, width, height, session int) TableModel {
 	return TableModel{
 		db:        d,
 		schema:    schema,
 		tableName: name,
+		kind:      db.KindTable,
 		width:     width,
 		height:    height,
 		sortCol:   -1,
+		session:   session,
 	}
 }
 
@@ -58,38 +82,29 @@ func (m TableModel) loadData() tea.Msg {
 			Desc:   m.sortDesc,
 		}
 	}
-	result, err := m.db.FetchTableData(context.Background(), m.schema, m.tableName, pageSize, m.offset, sort)
+	result, err := m.db.FetchTableData(context.Background(), m.schema, m.tableName, pageSize, m.offset, sort, m.filters)
 	if err != nil {
-		return dataErrMsg{err: err}
+		return dataErrMsg{session: m.session, err: err}
 	}
-	return dataLoadedMsg{result: result}
-}
-
-func (m TableModel) Init() tea.Cmd {
-	return m.loadData
+	msg := dataLoadedMsg{session: m.session, result: result}
+	if m.layoutKey != "" && m.cols.names == nil {
+		msg.layout, _ = db.LoadColumnLayout(m.layoutKey)
+	}
+	return msg
 }
 
-func (m *TableModel) calcColWidths() {
-	if m.result == nil {
-		return
+func (m TableModel) saveLayout() tea.Msg {
+	if m.layoutKey == "" {
+		return nil
 	}
-	m.colWidths = make([]int, len(m.result.Columns))
-	for i, col := range m.result.Columns {
-		m.colWidths[i] = len(col)
-	}
-	for _, row := range m.result.Rows {
-		for i, val := range row {
-			if idx := strings.IndexAny(val, "\n\r"); idx >= 0 {
-				val = val[:idx]
-			}
-			if len([]rune(val)) > m.colWidths[i] {
-				m.colWidths[i] = len([]rune(val))
-			}
-			if m.colWidths[i] > 30 {
-				m.colWidths[i] = 30
-			}
-		}
+	if err := db.SaveColumnLayout(m.layoutKey, m.cols.saved()); err != nil {
+		return tableNoticeMsg{text: "save column layout: " + err.Error()}
 	}
+	return nil
+}
+
+func (m TableModel) Init() tea.Cmd {
+	return m.loadData
 }
 
 func (m TableModel) Update(msg tea.Msg) (TableModel, tea.Cmd) {
@@ -98,12 +113,20 @@ func (m TableModel) Update(msg tea.Msg) (TableModel, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 	case dataLoadedMsg:
+		if msg.session != m.session {
+			return m, nil
+		}
 		if len(msg.result.Rows) == 0 && m.offset > 0 {
 			m.offset -= pageSize
+			m.recordLast = m.record
 			return m, m.loadData
 		}
 		m.result = msg.result
 		m.cursor = 0
+		if m.recordLast {
+			m.cursor = max(len(m.result.Rows)-1, 0)
+			m.recordLast = false
+		}
 		if len(m.result.Columns) == 0 {
 			m.colCursor = 0
 			m.sortCol = -1
@@ -120,13 +143,109 @@ func (m TableModel) Update(msg tea.Msg) (TableModel, tea.Cmd) {
 				m.sortDesc = false
 			}
 		}
-		m.calcColWidths()
+		if !m.cols.sameColumns(m.result.Columns) {
+			m.cols = newColumnLayout(m.result.Columns, msg.layout)
+		}
+		m.colCursor = m.cols.step(m.colCursor, 0)
+		m.colWidths = contentWidths(m.result)
+		m.followColumn()
+		if m.search != nil {
+			m.search.origin = gridMatch{m.cursor, -1}
+			m.search.find(m.result, m.cols.visible())
+		}
 	case dataErrMsg:
-		m.err = msg.err
+		if msg.session == m.session {
+			m.err = msg.err
+		}
+	case structureLoadedMsg:
+		if msg.session == m.session {
+			m.structure = msg.structure
+			m.structErr = nil
+		}
+	case structureErrMsg:
+		if msg.session == m.session {
+			m.structErr = msg.err
+		}
+	case referencesLoadedMsg:
+		if msg.session == m.session {
+			m.refs = &referenceList{column: msg.column, refs: msg.refs}
+		}
+	case profileLoadedMsg:
+		if msg.session == m.session && m.profile != nil && m.profile.column == msg.column {
+			m.profile.profile, m.profile.err = msg.profile, msg.err
+		}
 	case tea.KeyMsg:
+		if m.refs != nil {
+			return m.updateReferences(msg)
+		}
+		if m.profile != nil {
+			return m.updateProfile(msg)
+		}
+		if m.search != nil && m.search.typing {
+			return m.updateSearch(msg)
+		}
+		if msg.String() == "i" && m.tab == tabData {
+			return m, m.inspectCell()
+		}
+		if m.record {
+			return m.updateRecord(msg)
+		}
+		if msg.String() == "c" {
+			o := db.Object{Schema: m.schema, Name: m.tableName, Kind: m.kind}
+			return m, func() tea.Msg { return showDDLMsg{object: o} }
+		}
+		if msg.String() == "e" && m.kind == db.KindTable {
+			schema, table := m.schema, m.tableName
+			return m, func() tea.Msg { return showERDMsg{schema: schema, table: table} }
+		}
+		if msg.String() == "t" {
+			m.scrollX = 0
+			if m.tab == tabData {
+				m.tab = tabStructure
+				if m.structure == nil {
+					return m, m.loadStructure
+				}
+			} else {
+				m.tab = tabData
+			}
+			return m, nil
+		}
+		if m.tab == tabStructure {
+			return m.updateStructure(msg)
+		}
+		if m.search != nil {
+			switch msg.String() {
+			case "esc":
+				m.search = nil
+				return m, nil
+			case "n", "N":
+				delta := 1
+				if msg.String() == "N" {
+					delta = -1
+				}
+				m.search.step(delta)
+				m.jumpToMatch()
+				return m, nil
+			}
+		}
 		switch msg.String() {
 		case "esc", "q":
 			return m, func() tea.Msg { return GoBackMsg{} }
+		case "/":
+			if m.result != nil {
+				m.search = newGridSearch(m.cursor, m.colCursor)
+				return m, m.search.input.Focus()
+			}
+		case "S":
+			if m.result != nil && m.colCursor < len(m.result.Columns) {
+				m.profile = &columnProfileView{column: m.result.Columns[m.colCursor]}
+				return m, m.loadProfile(m.profile.column)
+			}
+		case "enter":
+			if m.result != nil && len(m.result.Rows) > 0 {
+				m.record = true
+				m.recordScroll = 0
+			}
 		case "j", "down":
 			if m.result != nil && m.cursor < len(m.result.Rows)-1 {
 				m.cursor++
@@ -135,14 +254,20 @@ func (m TableModel) Update(msg tea.Msg) (TableModel, tea.Cmd) {
 			if m.cursor > 0 {
 				m.cursor--
 			}
-		case "l", "right":
-			m.scrollX += 5
-		case "h", "left":
-			if m.scrollX >= 5 {
-				m.scrollX -= 5
-			} else {
-				m.scrollX = 0
+		case "l", "right", "d":
+			m.colCursor = m.cols.step(m.colCursor, 1)
+			m.followColumn()
+		case "h", "left", "a":
+			m.colCursor = m.cols.step(m.colCursor, -1)
+			m.followColumn()
+		case "home", "end":
+			if vis := m.cols.visible(); len(vis) > 0 {
+				m.colCursor = vis[0]
+				if msg.String() == "end" {
+					m.colCursor = vis[len(vis)-1]
+				}
 			}
+			m.followColumn()
 		case "n":
 			m.offset += pageSize
 			m.cursor = 0
@@ -155,13 +280,17 @@ func (m TableModel) Update(msg tea.Msg) (TableModel, tea.Cmd) {
 			}
 		case "r":
 			return m, m.loadData
-		case "d":
-			if m.result != nil && m.colCursor < len(m.result.Columns)-1 {
-				m.colCursor++
+		case "f":
+			return m, m.followKey()
+		case "<", ">", "x", "X", "P", "+", "-", "=", "0":
+			if m.result == nil || len(m.result.Columns) == 0 {
+				return m, nil
 			}
-		case "a":
-			if m.result != nil && m.colCursor > 0 {
-				m.colCursor--
+			col, changed := m.cols.handleKey(msg.String(), m.colCursor, m.colWidths[m.colCursor])
+			m.colCursor = col
+			m.followColumn()
+			if changed {
+				return m, m.saveLayout
 			}
 		case "o":
 			if m.result != nil && len(m.result.Columns) > 0 {
@@ -184,6 +313,135 @@ func (m TableModel) Update(msg tea.Msg) (TableModel, tea.Cmd) {
 				return m, m.loadData
 			}
 		}
+	default:
+		if m.search != nil && m.search.typing {
+			return m.updateSearch(msg)
+		}
+	}
+	return m, nil
+}
+
+func (m TableModel) gridColumns() ([]int, []string, []int) {
+	vis := m.cols.visible()
+	labels := make([]string, len(vis))
+	widths := make([]int, len(vis))
+	for k, i := range vis {
+		label := m.result.Columns[i]
+		if i == m.sortCol {
+			if m.sortDesc {
+				label += " ↓"
+			} else {
+				label += " ↑"
+			}
+		}
+		if i == m.colCursor {
+			label = "[" + label + "]"
+		}
+		labels[k] = label
+		widths[k] = m.cols.displayWidth(i, m.colWidths[i], label)
+	}
+	return vis, labels, widths
+}
+
+func (m *TableModel) followColumn() {
+	if m.result == nil {
+		return
+	}
+	vis, _, widths := m.gridColumns()
+	m.colOffset = m.cols.follow(m.colOffset, slices.Index(vis, m.colCursor), widths, m.width-1)
+}
+
+func (m TableModel) updateSearch(msg tea.Msg) (TableModel, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.String() == "esc" {
+		m.cursor, m.colCursor = m.search.origin.row, m.search.origin.col
+		m.search = nil
+		m.followColumn()
+		return m, nil
+	}
+	cmd, changed, _ := m.search.update(msg, m.colCursor)
+	if changed {
+		m.search.find(m.result, m.cols.visible())
+		m.jumpToMatch()
+	}
+	return m, cmd
+}
+
+func (m *TableModel) jumpToMatch() {
+	if g, ok := m.search.at(); ok {
+		m.cursor, m.colCursor = g.row, g.col
+		m.followColumn()
+	}
+}
+
+func (m TableModel) inspectCell() tea.Cmd {
+	if m.result == nil || m.cursor >= len(m.result.Rows) || m.colCursor >= len(m.result.Columns) {
+		return nil
+	}
+	msg := showCellMsg{column: m.tableName + "." + m.result.Columns[m.colCursor], value: m.result.Rows[m.cursor][m.colCursor]}
+	return func() tea.Msg { return msg }
+}
+
+func (m TableModel) updateRecord(msg tea.KeyMsg) (TableModel, tea.Cmd) {
+	rows := len(m.result.Rows)
+	maxScroll := max(recordLineCount(m.result.Columns, m.result.Rows[m.cursor], m.width)-(m.height-2), 0)
+	switch msg.String() {
+	case "esc", "q", "enter":
+		m.record = false
+	case "j", "down":
+		m.recordScroll = min(m.recordScroll+1, maxScroll)
+	case "k", "up":
+		m.recordScroll = max(m.recordScroll-1, 0)
+	case "pgdown", "ctrl+d":
+		m.recordScroll = min(m.recordScroll+m.height/2, maxScroll)
+	case "pgup", "ctrl+u":
+		m.recordScroll = max(m.recordScroll-m.height/2, 0)
+	case "n", "l", "right":
+		m.recordScroll = 0
+		if m.cursor < rows-1 {
+			m.cursor++
+		} else if rows == pageSize {
+			m.offset += pageSize
+			return m, m.loadData
+		}
+	case "p", "h", "left":
+		m.recordScroll = 0
+		if m.cursor > 0 {
+			m.cursor--
+		} else if m.offset >= pageSize {
+			m.offset -= pageSize
+			m.recordLast = true
+			return m, m.loadData
+		}
+	case "d":
+		m.colCursor = m.cols.step(m.colCursor, 1)
+		m.followColumn()
+	case "a":
+		m.colCursor = m.cols.step(m.colCursor, -1)
+		m.followColumn()
+	}
+	return m, nil
+}
+
+func (m TableModel) updateStructure(msg tea.KeyMsg) (TableModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		return m, func() tea.Msg { return GoBackMsg{} }
+	case "j", "down":
+		if m.structure != nil && m.structScroll < len(m.structure.lines())-(m.height-2) {
+			m.structScroll++
+		}
+	case "k", "up":
+		if m.structScroll > 0 {
+			m.structScroll--
+		}
+	case "l", "right":
+		m.scrollX += 5
+	case "h", "left":
+		m.scrollX = max(m.scrollX-5, 0)
+	case "r":
+		m.structure = nil
+		m.structErr = nil
+		return m, m.loadStructure
 	}
 	return m, nil
 }
@@ -231,9 +489,31 @@ var (
 	tblRowStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#E6EDF3"))
 	tblSelStyle    = lipgloss.NewStyle().Background(lipgloss.Color("#FF6F61")).Foreground(lipgloss.Color("#000000"))
 	tblBorderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#30363D"))
+	tblTabStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#8B949E"))
+	tblTabActive   = lipgloss.NewStyle().Bold(true).Underline(true).Foreground(lipgloss.Color("#E6EDF3"))
 )
 
+func (m TableModel) renderTitle(info string) string {
+	tabs := []string{"Data", "Structure"}
+	for i, t := range tabs {
+		if tableTab(i) == m.tab {
+			tabs[i] = tblTabActive.Render(t)
+		} else {
+			tabs[i] = tblTabStyle.Render(t)
+		}
+	}
+	title := tblHeaderStyle.Render(fmt.Sprintf(" %s.%s", m.schema, m.tableName)) + "  " +
+		strings.Join(tabs, tblTabStyle.Render(" │ "))
+	if len(m.filters) > 0 {
+		title += tblTabStyle.Render("  where " + describeFilters(m.filters))
+	}
+	return title + tblHeaderStyle.Render(info)
+}
+
 func (m TableModel) ViewPanel(w, h int) string {
+	if m.tab == tabStructure {
+		return m.viewStructure(w, h)
+	}
 	if m.err != nil {
 		errSty := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000"))
 		return errSty.Render(fmt.Sprintf(" Error: %v", m.err))
@@ -254,6 +534,10 @@ func (m TableModel) ViewPanel(w, h int) string {
 	if rowCount == 0 {
 		startRow = 0
 	}
+	if m.record && m.cursor < rowCount {
+		title := m.renderTitle(fmt.Sprintf("  row %d  (%d – %d)", m.offset+m.cursor+1, startRow, m.offset+rowCount))
+		return renderRecord(title, m.result.Columns, m.result.Rows[m.cursor], m.colCursor, m.recordScroll, w, h)
+	}
 	sortInfo := ""
 	if m.sortCol >= 0 && m.sortCol < len(m.result.Columns) {
 		dir := "ASC"
@@ -262,37 +546,35 @@ func (m TableModel) ViewPanel(w, h int) string {
 		}
 		sortInfo = fmt.Sprintf("  · sort: %s %s", m.result.Columns[m.sortCol], dir)
 	}
-	title := fmt.Sprintf(" %s.%s  (%d – %d)%s", m.schema, m.tableName, startRow, m.offset+rowCount, sortInfo)
-	b.WriteString(tblHeaderStyle.Render(title) + "\n\n")
-
-	displayWidths := make([]int, len(m.result.Columns))
-	var headerCells, separators []string
-	for i, col := range m.result.Columns {
-		label := col
-		if i == m.sortCol {
-			if m.sortDesc {
-				label += " ↓"
-			} else {
-				label += " ↑"
-			}
-		}
-		if i == m.colCursor {
-			label = "[" + label + "]"
-		}
-		cw := m.colWidths[i]
-		if lw := len([]rune(label)); lw > cw {
-			cw = lw
-		}
-		displayWidths[i] = cw
-		headerCells = append(headerCells, padRight(label, cw))
-		separators = append(separators, strings.Repeat("─", cw))
+	vis, labels, widths := m.gridColumns()
+	first := m.cols.follow(m.colOffset, slices.Index(vis, m.colCursor), widths, w-1)
+	sortInfo += m.cols.describe() + m.cols.shownRange(first, widths, w-1)
+	if m.search != nil {
+		sortInfo += m.search.status(m.result.Columns)
+	}
+	b.WriteString(m.renderTitle(fmt.Sprintf("  (%d – %d)%s", startRow, m.offset+rowCount, sortInfo)) + "\n")
+	if m.search != nil && m.search.typing {
+		b.WriteString(" " + m.search.input.View())
+	}
+	b.WriteString("\n")
+	if m.refs != nil {
+		b.WriteString(m.refs.view(w, h-3))
+		return b.String()
+	}
+	if m.profile != nil {
+		b.WriteString(m.profile.view(w, h-2))
+		return b.String()
 	}
 
-	headerLine := " │ " + strings.Join(headerCells, " │ ") + " │"
-	sepLine := " ├─" + strings.Join(separators, "─┼─") + "─┤"
+	headerCells := make([]string, len(vis))
+	separators := make([]string, len(vis))
+	for k, label := range labels {
+		headerCells[k] = padRight(label, widths[k])
+		separators[k] = strings.Repeat("─", widths[k])
+	}
 
-	b.WriteString(tblHeaderStyle.Render(clipLine(truncateLine(headerLine, m.scrollX, w), w)) + "\n")
-	b.WriteString(tblBorderStyle.Render(clipLine(truncateLine(sepLine, m.scrollX, w), w)) + "\n")
+	b.WriteString(tblHeaderStyle.Render(" "+m.cols.gridLine(headerCells, gridCellBorders, first, w-1)) + "\n")
+	b.WriteString(tblBorderStyle.Render(" "+m.cols.gridLine(separators, gridSepBorders, first, w-1)) + "\n")
 
 	viewStart := 0
 	if m.cursor >= visibleRows {
@@ -305,16 +587,23 @@ func (m TableModel) ViewPanel(w, h int) string {
 
 	for i := viewStart; i < endRow; i++ {
 		row := m.result.Rows[i]
-		var cells []string
-		for j, val := range row {
-			cells = append(cells, padRight(val, displayWidths[j]))
+		cells := make([]string, len(vis))
+		for k, j := range vis {
+			cells[k] = padRight(row[j], widths[k])
 		}
-		line := " │ " + strings.Join(cells, " │ ") + " │"
-		line = clipLine(truncateLine(line, m.scrollX, w), w)
+		line := " " + m.cols.gridLine(cells, gridCellBorders, first, w-1)
+		style := tblRowStyle
 		if i == m.cursor {
-			b.WriteString(tblSelStyle.Render(line) + "\n")
+			style = tblSelStyle
+		}
+		if m.search != nil && m.search.query() != "" {
+			current := -1
+			if g, ok := m.search.at(); ok && g.row == i {
+				current = g.col
+			}
+			b.WriteString(m.search.highlight(line, style, cells, m.cols.cellOffsets(cells, first, 1), vis, current) + "\n")
 		} else {
-			b.WriteString(tblRowStyle.Render(line) + "\n")
+			b.WriteString(style.Render(line) + "\n")
 		}
 	}
 